@@ -0,0 +1,359 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/julienschmidt/httprouter"
+	"github.com/layer8s/home-dashboard-app/internal/db"
+	"github.com/layer8s/home-dashboard-app/templates"
+)
+
+// pendingLink is a first login with a provider whose verified email address
+// matches an existing user. It's held until the person confirms whether the new
+// identity should be added to that user or get a user of its own.
+type pendingLink struct {
+	Provider string       `json:"provider"`
+	Identity userIdentity `json:"identity"`
+	UserID   int64        `json:"user_id"`
+	UserName string       `json:"user_name"`
+	Remember bool         `json:"remember,omitempty"`
+}
+
+func pendingLinkKey(token string) string {
+	return "pending_link:" + token
+}
+
+// completeLogin finds or creates the user behind identity once the provider has
+// authenticated it, and signs them in. Each user can have identities with several
+// providers:
+//
+//   - an identity seen before signs in its user;
+//   - when the attempt was started from "link another account", the identity is
+//     added to the signed-in user;
+//   - a new identity whose verified email matches an existing user is held until
+//     the person confirms whether to link it;
+//   - anything else creates a new user.
+func (app *application) completeLogin(w http.ResponseWriter, r *http.Request, provider string, identity userIdentity, attempt loginAttempt) {
+	// Addresses are compared as normalizeEmail leaves them, as invitations are.
+	identity.Email = normalizeEmail(identity.Email)
+	userID, err := app.queries.RecordIdentityLogin(r.Context(), db.RecordIdentityLoginParams{
+		Provider:      provider,
+		ProviderID:    identity.Subject,
+		Email:         nullableString(identity.Email),
+		EmailVerified: identity.EmailVerified,
+	})
+	switch {
+	case err == nil:
+		if attempt.LinkUserID != 0 && attempt.LinkUserID != userID {
+			app.identityConflictResponse(w, r, provider)
+			return
+		}
+		if attempt.LinkUserID == 0 {
+			if err := app.signIn(w, r, userID, provider, identity, attempt.Remember); err != nil {
+				app.serverErrorResponse(w, r, err)
+				return
+			}
+		}
+		http.Redirect(w, r, "/v1/dashboard", http.StatusSeeOther)
+		return
+	case !errors.Is(err, sql.ErrNoRows):
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if attempt.LinkUserID != 0 {
+		if err := app.linkIdentity(r.Context(), attempt.LinkUserID, provider, identity); err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		app.requestLogger(r).Info("identity linked", "user_id", attempt.LinkUserID, "provider", provider)
+		http.Redirect(w, r, "/v1/dashboard", http.StatusSeeOther)
+		return
+	}
+
+	if identity.EmailVerified {
+		match, err := app.queries.FindUserByVerifiedEmail(r.Context(), nullableString(identity.Email))
+		switch {
+		case err == nil:
+			if err := app.holdPendingLink(w, r, pendingLink{Provider: provider, Identity: identity, UserID: match.ID, UserName: match.Name, Remember: attempt.Remember}); err != nil {
+				app.serverErrorResponse(w, r, err)
+				return
+			}
+			http.Redirect(w, r, "/v1/account/link/confirm", http.StatusSeeOther)
+			return
+		case !errors.Is(err, sql.ErrNoRows):
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	userID, err = app.createAccount(r.Context(), provider, identity)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if err := app.signIn(w, r, userID, provider, identity, attempt.Remember); err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	http.Redirect(w, r, "/v1/dashboard", http.StatusSeeOther)
+}
+
+// signIn records the identity the user signed in with in their session, and
+// tracks the session against userID. The session gets a new ID, and with remember
+// set lasts for the remember-me lifetime. Any league invitations waiting for the
+// user are accepted on the way in. An error means the session couldn't be saved
+// or tracked and the user isn't signed in; failing to accept invitations is only
+// logged.
+func (app *application) signIn(w http.ResponseWriter, r *http.Request, userID int64, provider string, identity userIdentity, remember bool) error {
+	session, _ := app.sessionStore.Get(r, "auth-session")
+	if session.ID != "" {
+		if err := app.forgetSession(r.Context(), session.ID); err != nil {
+			app.logError(r, err)
+		}
+		session.ID = ""
+	}
+	remember = remember && app.config.Session.RememberMeMaxAge > 0
+	session.Options.MaxAge = int(app.sessionLifetime(remember).Seconds())
+	session.Values["user_id"] = identity.Subject
+	session.Values["email"] = identity.Email
+	session.Values["name"] = identity.Name
+	session.Values["provider"] = provider
+	session.Values["id_token"] = identity.IDToken
+	session.Values["authenticated"] = true
+	inviteToken, _ := session.Values[inviteSessionKey].(string)
+	delete(session.Values, inviteSessionKey)
+	if err := session.Save(r, w); err != nil {
+		return err
+	}
+	if err := app.trackSession(r, userID, session.ID, provider, remember); err != nil {
+		// An untracked session would be signed out on its next request anyway.
+		app.clearSession(w, r, session)
+		return err
+	}
+	if err := app.acceptInvitations(r.Context(), userID, inviteToken); err != nil {
+		app.logError(r, err)
+	}
+	return nil
+}
+
+// createAccount creates a user with identity as its only identity and returns
+// the user's ID.
+func (app *application) createAccount(ctx context.Context, provider string, identity userIdentity) (int64, error) {
+	tx, err := app.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	qtx := app.txQueries(tx)
+	user, err := qtx.CreateUser(ctx, db.CreateUserParams{
+		Name:      identity.Name,
+		Email:     nullableString(identity.Email),
+		Activated: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := linkIdentity(ctx, qtx, user.ID, provider, identity); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	app.loggerFromContext(ctx).Info("user created", "id", user.ID, "provider", provider)
+	return user.ID, nil
+}
+
+func (app *application) linkIdentity(ctx context.Context, userID int64, provider string, identity userIdentity) error {
+	return linkIdentity(ctx, app.queries, userID, provider, identity)
+}
+
+func linkIdentity(ctx context.Context, q *db.Queries, userID int64, provider string, identity userIdentity) error {
+	_, err := q.CreateUserIdentity(ctx, db.CreateUserIdentityParams{
+		UserID:        userID,
+		Provider:      provider,
+		ProviderID:    identity.Subject,
+		Email:         nullableString(normalizeEmail(identity.Email)),
+		EmailVerified: identity.EmailVerified,
+	})
+	return err
+}
+
+func (app *application) holdPendingLink(w http.ResponseWriter, r *http.Request, link pendingLink) error {
+	token, err := generateState()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(link)
+	if err != nil {
+		return err
+	}
+	if err := app.redisClient.Set(r.Context(), pendingLinkKey(token), data, loginAttemptTTL).Err(); err != nil {
+		return fmt.Errorf("storing pending link: %w", err)
+	}
+
+	session, _ := app.sessionStore.Get(r, "auth-session")
+	session.Values["pending_link"] = token
+	return session.Save(r, w)
+}
+
+// pendingLinkFromSession returns the link waiting for confirmation in this
+// browser. With consume set it's deleted, so it can only be acted on once.
+func (app *application) pendingLinkFromSession(r *http.Request, consume bool) (pendingLink, bool, error) {
+	session, _ := app.sessionStore.Get(r, "auth-session")
+	token, _ := session.Values["pending_link"].(string)
+	if token == "" {
+		return pendingLink{}, false, nil
+	}
+
+	var data string
+	var err error
+	if consume {
+		var get *redis.StringCmd
+		_, err = app.redisClient.TxPipelined(r.Context(), func(pipe redis.Pipeliner) error {
+			get = pipe.Get(r.Context(), pendingLinkKey(token))
+			pipe.Del(r.Context(), pendingLinkKey(token))
+			return nil
+		})
+		if err == nil {
+			data = get.Val()
+		}
+	} else {
+		data, err = app.redisClient.Get(r.Context(), pendingLinkKey(token)).Result()
+	}
+	if errors.Is(err, redis.Nil) {
+		return pendingLink{}, false, nil
+	}
+	if err != nil {
+		return pendingLink{}, false, err
+	}
+
+	var link pendingLink
+	if err := json.Unmarshal([]byte(data), &link); err != nil {
+		return pendingLink{}, false, err
+	}
+	return link, true, nil
+}
+
+// linkConfirmHandler asks whether a new identity should join the existing user
+// with the same verified email address.
+func (app *application) linkConfirmHandler(w http.ResponseWriter, r *http.Request) {
+	link, ok, err := app.pendingLinkFromSession(r, false)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if !ok {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	err = templates.Base(
+		templates.LinkConfirm(link.Provider, link.Identity.Email, link.UserName),
+	).Render(r.Context(), w)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// linkConfirmSubmitHandler acts on the answer: "link" adds the identity to the
+// existing user, "separate" gives it a new user. Either way the person is then
+// signed in with it.
+func (app *application) linkConfirmSubmitHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+	choice := r.PostForm.Get("choice")
+	if choice != "link" && choice != "separate" {
+		app.badRequestResponse(w, r, errors.New(`choice must be "link" or "separate"`))
+		return
+	}
+
+	link, ok, err := app.pendingLinkFromSession(r, true)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if !ok {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	userID := link.UserID
+	if choice == "link" {
+		err = app.linkIdentity(r.Context(), link.UserID, link.Provider, link.Identity)
+	} else {
+		userID, err = app.createAccount(r.Context(), link.Provider, link.Identity)
+	}
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if choice == "link" {
+		app.requestLogger(r).Info("identity linked by email", "user_id", link.UserID, "provider", link.Provider)
+	}
+
+	session, _ := app.sessionStore.Get(r, "auth-session")
+	delete(session.Values, "pending_link")
+	if err := app.signIn(w, r, userID, link.Provider, link.Identity, link.Remember); err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	http.Redirect(w, r, "/v1/dashboard", http.StatusSeeOther)
+}
+
+// HandleLink starts a login with another provider on behalf of the signed-in
+// user, so the identity it returns is added to their account.
+func (app *application) HandleLink(w http.ResponseWriter, r *http.Request) {
+	provider := httprouter.ParamsFromContext(r.Context()).ByName("provider")
+
+	p, err := app.authManager.Provider(r.Context(), provider)
+	switch {
+	case errors.Is(err, errProviderNotFound):
+		app.notFoundResponse(w, r)
+		return
+	case err != nil:
+		app.providerUnavailableResponse(w, r, provider, err)
+		return
+	}
+
+	user, err := app.sessionUser(r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	state, opts, err := app.startLogin(w, r, p, user.ID, false)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, p.Config.AuthCodeURL(state, opts...), http.StatusTemporaryRedirect)
+}
+
+// sessionUser returns the user behind the identity the session was signed in
+// with. It must only be called behind requireAuthenticated.
+func (app *application) sessionUser(r *http.Request) (db.GetUserByProviderRow, error) {
+	session, _ := app.sessionStore.Get(r, "auth-session")
+	provider, _ := session.Values["provider"].(string)
+	subject, _ := session.Values["user_id"].(string)
+
+	return app.queries.GetUserByProvider(r.Context(), db.GetUserByProviderParams{
+		Provider:   provider,
+		ProviderID: subject,
+	})
+}
+
+func nullableString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gorilla/sessions"
+)
+
+// unsavableStore is a session store that can't save sessions, as when Redis is
+// down.
+type unsavableStore struct{}
+
+func (s unsavableStore) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+func (s unsavableStore) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(s, name)
+	session.Options = &sessions.Options{Path: "/"}
+	session.IsNew = true
+	return session, nil
+}
+
+func (s unsavableStore) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	return errors.New("session store unavailable")
+}
+
+func TestCompleteLoginSessionNotSaved(t *testing.T) {
+	app, _, mock := newTestApplication(t)
+	app.sessionStore = unsavableStore{}
+
+	mock.ExpectQuery("-- name: RecordIdentityLogin").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(4))
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/auth/github/callback", nil)
+	w := httptest.NewRecorder()
+	app.completeLogin(w, r, "github", userIdentity{Subject: "42", Email: "alice@example.com"}, loginAttempt{})
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if location := w.Header().Get("Location"); location != "" {
+		t.Errorf("redirected to %q without a session", location)
+	}
+}
+
+func TestCompleteLoginSessionNotTracked(t *testing.T) {
+	app, mr, mock := newTestApplication(t)
+	const userID = 4
+
+	// Tracking the session fails as the user's session set isn't a set.
+	if err := mr.Set(userSessionsKey(userID), "not a set"); err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectQuery("-- name: RecordIdentityLogin").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID))
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/auth/github/callback", nil)
+	w := httptest.NewRecorder()
+	app.completeLogin(w, r, "github", userIdentity{Subject: "42", Email: "alice@example.com"}, loginAttempt{})
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	for _, key := range mr.Keys() {
+		if strings.HasPrefix(key, sessionKeyPrefix) {
+			t.Errorf("session %s kept after tracking it failed", key)
+		}
+	}
+}
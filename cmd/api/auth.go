@@ -3,85 +3,20 @@ package main
 import (
 	"context"
 	"crypto/rand"
-	"database/sql"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
-	"sync"
 
-	"github.com/coreos/go-oidc/v3/oidc"
-	"github.com/layer8s/home-dashboard-app/internal/db"
+	"github.com/julienschmidt/httprouter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/oauth2"
 )
 
-type OIDCProvider struct {
-	Provider     *oidc.Provider
-	Config       oauth2.Config
-	Verifier     *oidc.IDTokenVerifier
-	Name         string
-	ClientID     string
-	ClientSecret string
-	Issuer       string
-}
-
-type AuthManager struct {
-	providers map[string]*OIDCProvider
-	mu        sync.RWMutex
-}
-
-func NewAuthManager() *AuthManager {
-	return &AuthManager{
-		providers: make(map[string]*OIDCProvider),
-		mu:        sync.RWMutex{},
-	}
-}
-
-func (am *AuthManager) RegisterProvider(ctx context.Context, config ProviderConfig) error {
-
-	if am == nil {
-		return fmt.Errorf("AuthManager is nil")
-	}
-	if am.providers == nil {
-		am.providers = make(map[string]*OIDCProvider)
-	}
-	am.mu.Lock()
-	defer am.mu.Unlock()
-
-	provider, err := oidc.NewProvider(ctx, config.ExtraConfig["issuer"])
-	if err != nil {
-		return fmt.Errorf("failed to initialize provider: %w", err)
-	}
-
-	oidcConfig := &oidc.Config{
-		ClientID: config.ClientID,
-	}
-
-	// Configure the OAuth2 config
-	oauth2Config := oauth2.Config{
-		ClientID:     config.ClientID,
-		ClientSecret: config.ClientSecret,
-		RedirectURL:  fmt.Sprintf("%s/v1/auth/%s/callback", config.BaseCallbackURL, config.Name),
-		Endpoint:     provider.Endpoint(),
-		Scopes:       append([]string{oidc.ScopeOpenID, "profile", "email"}, config.Scopes...),
-	}
-
-	am.providers[config.Name] = &OIDCProvider{
-		Provider:     provider,
-		Config:       oauth2Config,
-		Verifier:     provider.Verifier(oidcConfig),
-		Name:         config.Name,
-		ClientID:     config.ClientID,
-		ClientSecret: config.ClientSecret,
-		Issuer:       config.ExtraConfig["issuer"],
-	}
-
-	return nil
-}
-
 func generateState() (string, error) {
 	b := make([]byte, 32)
 	if _, err := rand.Read(b); err != nil {
@@ -94,27 +29,23 @@ func (a *application) HandleAuth(w http.ResponseWriter, r *http.Request) {
 	provider := strings.TrimPrefix(r.URL.Path, "/v1/auth/")
 	provider = strings.TrimSuffix(provider, "/")
 
-	a.authManager.mu.RLock()
-	p, exists := a.authManager.providers[provider]
-	a.authManager.mu.RUnlock()
-
-	if !exists {
+	p, err := a.authManager.Provider(r.Context(), provider)
+	switch {
+	case errors.Is(err, errProviderNotFound):
 		a.notFoundResponse(w, r)
 		return
+	case err != nil:
+		a.providerUnavailableResponse(w, r, provider, err)
+		return
 	}
 
-	state, err := generateState()
+	state, opts, err := a.startLogin(w, r, p, 0, r.URL.Query().Get("remember") == "true")
 	if err != nil {
 		a.serverErrorResponse(w, r, err)
 		return
 	}
 
-	// Store state in session
-	session, _ := a.sessionStore.Get(r, "auth-session")
-	session.Values["state"] = state
-	session.Save(r, w)
-
-	authURL := p.Config.AuthCodeURL(state)
+	authURL := p.Config.AuthCodeURL(state, opts...)
 	http.Redirect(w, r, authURL, http.StatusTemporaryRedirect)
 }
 
@@ -122,39 +53,50 @@ func (a *application) HandleCallback(w http.ResponseWriter, r *http.Request) {
 	provider := strings.TrimPrefix(r.URL.Path, "/v1/auth/")
 	provider = strings.TrimSuffix(strings.TrimSuffix(provider, "/callback"), "/")
 
-	a.logger.Info("auth callback received",
+	a.requestLogger(r).Info("auth callback received",
 		"provider", provider,
 		"state", r.URL.Query().Get("state"),
 		"code", r.URL.Query().Get("code") != "")
 
-	a.authManager.mu.RLock()
-	p, exists := a.authManager.providers[provider]
-	a.authManager.mu.RUnlock()
-
-	if !exists {
-		a.logger.Error("provider not found", "provider", provider)
+	p, err := a.authManager.Provider(r.Context(), provider)
+	switch {
+	case errors.Is(err, errProviderNotFound):
+		a.requestLogger(r).Error("provider not found", "provider", provider)
 		a.notFoundResponse(w, r)
 		return
+	case err != nil:
+		a.providerUnavailableResponse(w, r, provider, err)
+		return
 	}
 
-	// Verify state
-	session, _ := a.sessionStore.Get(r, "auth-session")
-	if r.URL.Query().Get("state") != session.Values["state"] {
+	attempt, err := a.finishLogin(r, provider, r.URL.Query().Get("state"))
+	switch {
+	case errors.Is(err, errInvalidLoginState):
 		a.invalidAuthenticationTokenResponse(w, r)
 		return
+	case err != nil:
+		a.serverErrorResponse(w, r, err)
+		return
 	}
 
-	// Exchange code for token
-	oauth2Token, err := p.Config.Exchange(r.Context(), r.URL.Query().Get("code"))
+	// Exchange code for token, tracing the call to the provider's token endpoint
+	ctx, span := tracer.Start(r.Context(), "oidc token exchange", trace.WithAttributes(attribute.String("oidc.provider", provider)))
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, tracedHTTPClient())
+	oauth2Token, err := p.Config.Exchange(ctx, r.URL.Query().Get("code"), oauth2.VerifierOption(attempt.Verifier))
 	if err != nil {
-		a.logger.Error("token exchange failed",
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+	if err != nil {
+		a.requestLogger(r).Error("token exchange failed",
 			"error", err,
 			"provider", provider,
 			"error_type", fmt.Sprintf("%T", err))
 
 		var oauthError *oauth2.RetrieveError
 		if errors.As(err, &oauthError) {
-			a.logger.Error("oauth2 retrieve error details",
+			a.requestLogger(r).Error("oauth2 retrieve error details",
 				"status_code", oauthError.Response.StatusCode,
 				"body", string(oauthError.Body))
 		}
@@ -162,90 +104,65 @@ func (a *application) HandleCallback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Extract the ID Token
-	rawIDToken, ok := oauth2Token.Extra("id_token").(string)
-	if !ok {
-		a.serverErrorResponse(w, r, errors.New("no id_token in token response"))
-		return
-	}
-
-	// Verify the ID Token
-	idToken, err := p.Verifier.Verify(r.Context(), rawIDToken)
+	// Claims may need fetching from the userinfo endpoint, so trace those calls too.
+	ctx = context.WithValue(r.Context(), oauth2.HTTPClient, tracedHTTPClient())
+	identity, err := p.identity(ctx, oauth2Token, attempt.Nonce)
 	if err != nil {
 		a.serverErrorResponse(w, r, err)
 		return
 	}
 
-	// Get user info
-	var claims struct {
-		Email    string `json:"email"`
-		Sub      string `json:"sub"`
-		Name     string `json:"name"`
-		Nickname string `json:"nickname"`
+	a.completeLogin(w, r, provider, identity, attempt)
+}
+
+// HandleLogout clears the local session and, when the provider the user signed in
+// with supports RP-initiated logout, sends them on to its end_session_endpoint so
+// they're signed out there too. Otherwise, or if the provider is unavailable, the
+// logout is local only and the user goes straight to the post-logout URL.
+func (a *application) HandleLogout(w http.ResponseWriter, r *http.Request) {
+	session, _ := a.sessionStore.Get(r, "auth-session")
+
+	// The session knows which provider the user actually signed in with; the one
+	// in the path only matters if the session has already gone.
+	provider, _ := session.Values["provider"].(string)
+	if provider == "" {
+		provider = httprouter.ParamsFromContext(r.Context()).ByName("provider")
 	}
-	if err := idToken.Claims(&claims); err != nil {
+	idToken, _ := session.Values["id_token"].(string)
+	sessionID := session.ID
+
+	session.Options.MaxAge = -1
+	if err := session.Save(r, w); err != nil {
 		a.serverErrorResponse(w, r, err)
 		return
 	}
+	if err := a.forgetSession(r.Context(), sessionID); err != nil {
+		a.logError(r, err)
+	}
 
-	// Store user info in session
-	session.Values["user_id"] = claims.Sub
-	session.Values["email"] = claims.Email
-	session.Values["name"] = claims.Name
-	session.Values["provider"] = provider
-	session.Values["authenticated"] = true
-	session.Save(r, w)
-
-	//Return user info
-	// err = a.writeJSON(w, http.StatusOK, envelope{"user": claims}, nil)
-	// if err != nil {
-	// 	a.serverErrorResponse(w, r, err)
-	// }
-
-	var nullableEmail sql.NullString
-	if claims.Email != "" {
-		nullableEmail = sql.NullString{String: claims.Email, Valid: true}
-	} else {
-		nullableEmail = sql.NullString{Valid: false}
+	postLogoutURL := a.config.Auth.PostLogoutRedirectURL
+
+	p, err := a.authManager.Provider(r.Context(), provider)
+	if err != nil || p.EndSessionURL == "" {
+		if err != nil && !errors.Is(err, errProviderNotFound) {
+			a.requestLogger(r).Warn("signing out locally only", "provider", provider, "error", err)
+		}
+		http.Redirect(w, r, postLogoutURL, http.StatusSeeOther)
+		return
 	}
 
-	//Upsert user info into DB
-	user, err := a.queries.UpsertUser(r.Context(), db.UpsertUserParams{
-		Name:       claims.Name,
-		Email:      nullableEmail,
-		Activated:  true,
-		Provider:   provider,
-		ProviderID: claims.Sub,
-	})
+	logoutURL, err := url.Parse(p.EndSessionURL)
 	if err != nil {
-		a.logger.Error("failed to upsert user", err)
+		a.serverErrorResponse(w, r, err)
+		return
 	}
+	q := logoutURL.Query()
+	q.Set("client_id", p.ClientID)
+	q.Set("post_logout_redirect_uri", postLogoutURL)
+	if idToken != "" {
+		q.Set("id_token_hint", idToken)
+	}
+	logoutURL.RawQuery = q.Encode()
 
-	fmt.Printf("User inserted or updated: ID=%d, Version=%d, CreatedAt=%s\n",
-		user.ID, user.Version, user.CreatedAt)
-
-	http.Redirect(w, r, "/v1/dashboard", http.StatusSeeOther)
-}
-
-func (a *application) HandleLogout(w http.ResponseWriter, r *http.Request) {
-	session, _ := a.sessionStore.Get(r, "auth-session")
-	session.Options.MaxAge = -1
-	session.Save(r, w)
-
-	// err := a.writeJSON(w, http.StatusOK, envelope{"message": "Successfully logged out"}, nil)
-	// if err != nil {
-	// 	a.serverErrorResponse(w, r, err)
-	// }
-	domain := os.Getenv("AUTH0_DOMAIN")
-	clientID := os.Getenv("AUTH0_CLIENT_ID")
-	postLogoutRedirectURI := "http://localhost:4000/"
-
-	logoutURL := fmt.Sprintf(
-		"https://%s/oidc/logout?client_id=%s&post_logout_redirect_uri=%s",
-		domain,
-		url.QueryEscape(clientID),
-		url.QueryEscape(postLogoutRedirectURI),
-	)
-
-	http.Redirect(w, r, logoutURL, http.StatusSeeOther)
+	http.Redirect(w, r, logoutURL.String(), http.StatusSeeOther)
 }
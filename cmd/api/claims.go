@@ -0,0 +1,597 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/layer8s/home-dashboard-app/internal/data"
+	"github.com/layer8s/home-dashboard-app/internal/db"
+	"github.com/layer8s/home-dashboard-app/internal/validator"
+	"github.com/layer8s/home-dashboard-app/templates"
+	"github.com/lib/pq"
+)
+
+// errClaimNotOpen is returned when a claim is no longer in a status the change
+// asked for can be made from, usually because someone else got there first.
+var errClaimNotOpen = errors.New("claim not open")
+
+// teamClaimInput is what's asked for when claiming a team, through the JSON API
+// or the dashboard form.
+type teamClaimInput struct {
+	Owner   string `json:"owner"`
+	TeamID  int32  `json:"team_id"`
+	Message string `json:"message"`
+}
+
+func teamClaimFromModel(c db.TeamClaim) data.TeamClaim {
+	return teamClaimFromRow(db.ListLeagueTeamClaimsRow{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		LeagueID:  c.LeagueID,
+		Owner:     c.Owner,
+		TeamID:    c.TeamID,
+		Status:    c.Status,
+		Message:   c.Message,
+		DecidedBy: c.DecidedBy,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	})
+}
+
+func teamClaimFromRow(row db.ListLeagueTeamClaimsRow) data.TeamClaim {
+	claim := data.TeamClaim{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		UserName:  row.UserName,
+		LeagueID:  row.LeagueID,
+		Status:    row.Status,
+		Message:   row.Message,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+	if row.Owner.Valid {
+		claim.Owner = &row.Owner.String
+	}
+	if row.TeamID.Valid {
+		claim.TeamID = &row.TeamID.Int32
+	}
+	if row.DecidedBy.Valid {
+		claim.DecidedBy = &row.DecidedBy.Int64
+	}
+	return claim
+}
+
+// createTeamClaim validates input and files a claim by userID on an owner or team
+// in the ESPN league leagueID. It returns nil and no error if validation failed;
+// the problems are left in v.
+func (app *application) createTeamClaim(ctx context.Context, userID int64, leagueID int32, input teamClaimInput, v *validator.Validator) (*data.TeamClaim, error) {
+	input.Owner = strings.TrimSpace(input.Owner)
+	if data.ValidateTeamClaim(v, input.Owner, input.TeamID, input.Message); !v.Valid() {
+		return nil, nil
+	}
+
+	owner := nullableString(input.Owner)
+	exists, err := app.queries.TeamClaimTargetExists(ctx, db.TeamClaimTargetExistsParams{
+		LeagueId: leagueID,
+		Owners:   owner,
+		TeamId:   input.TeamID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Problems with the claim's target are reported against whichever of owner
+	// and team_id was given.
+	field := "team_id"
+	if owner.Valid {
+		field = "owner"
+	}
+	if !exists {
+		if owner.Valid {
+			v.AddError(field, "no team in this league has this owner")
+		} else {
+			v.AddError(field, "no team in this league has this ID")
+		}
+		return nil, nil
+	}
+
+	tx, err := app.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := app.txQueries(tx)
+	row, err := qtx.InsertTeamClaim(ctx, db.InsertTeamClaimParams{
+		UserID:   userID,
+		LeagueID: leagueID,
+		Owner:    owner,
+		TeamID:   sql.NullInt32{Int32: input.TeamID, Valid: input.TeamID != 0},
+		Message:  input.Message,
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			v.AddError(field, "you have already claimed this")
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	err = qtx.InsertTeamClaimEvent(ctx, db.InsertTeamClaimEventParams{
+		ClaimID: row.ID,
+		Status:  row.Status,
+		ActorID: sql.NullInt64{Int64: userID, Valid: true},
+		Note:    input.Message,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	app.loggerFromContext(ctx).Info("team claimed", "claim_id", row.ID, "user_id", userID, "league_id", leagueID)
+	claim := teamClaimFromModel(row)
+	return &claim, nil
+}
+
+// setTeamClaimStatus moves a claim to status, provided it's currently in one of
+// from, and records who did it in the claim's history.
+func (app *application) setTeamClaimStatus(ctx context.Context, claimID int64, status string, from []string, actorID int64, note string) (*data.TeamClaim, error) {
+	tx, err := app.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := app.txQueries(tx)
+	row, err := qtx.UpdateTeamClaimStatus(ctx, db.UpdateTeamClaimStatusParams{
+		Status:       status,
+		DecidedBy:    sql.NullInt64{Int64: actorID, Valid: true},
+		ID:           claimID,
+		FromStatuses: from,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errClaimNotOpen
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = qtx.InsertTeamClaimEvent(ctx, db.InsertTeamClaimEventParams{
+		ClaimID: claimID,
+		Status:  status,
+		ActorID: sql.NullInt64{Int64: actorID, Valid: true},
+		Note:    note,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	app.loggerFromContext(ctx).Info("team claim updated", "claim_id", claimID, "status", status, "by", actorID)
+	claim := teamClaimFromModel(row)
+	return &claim, nil
+}
+
+// decideTeamClaim is a commissioner's decision on claim in the league they're
+// acting in. Nobody decides on their own claims.
+func (app *application) decideTeamClaim(ctx context.Context, claim db.TeamClaim, actorID int64, status, note string) (*data.TeamClaim, error) {
+	if claim.UserID == actorID {
+		return nil, errClaimNotOpen
+	}
+	return app.setTeamClaimStatus(ctx, claim.ID, status, data.ClaimDecisionFrom(status), actorID, note)
+}
+
+// claimInLeague returns the claim with the :claim_id of r if it was made in the
+// league r is about.
+func (app *application) claimInLeague(r *http.Request) (db.TeamClaim, error) {
+	id, err := app.readNestedIDParam(r, "claim_id")
+	if err != nil {
+		return db.TeamClaim{}, sql.ErrNoRows
+	}
+	claim, err := app.queries.GetTeamClaim(r.Context(), id)
+	if err != nil {
+		return db.TeamClaim{}, err
+	}
+	if claim.LeagueID != contextGetLeagueAccess(r).League.LeagueId {
+		return db.TeamClaim{}, sql.ErrNoRows
+	}
+	return claim, nil
+}
+
+// createTeamClaimHandler lets any signed-in user claim an owner or a team in the
+// league, for a commissioner to approve.
+func (app *application) createTeamClaimHandler(w http.ResponseWriter, r *http.Request) {
+	access := contextGetLeagueAccess(r)
+	user := contextGetUser(r)
+
+	var input teamClaimInput
+	if err := app.readJSON(w, r, &input); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	claim, err := app.createTeamClaim(r.Context(), user.ID, access.League.LeagueId, input, v)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"claim": claim}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *application) listTeamClaimsHandler(w http.ResponseWriter, r *http.Request) {
+	access := contextGetLeagueAccess(r)
+
+	rows, err := app.queries.ListLeagueTeamClaims(r.Context(), db.ListLeagueTeamClaimsParams{
+		LeagueID: access.League.LeagueId,
+		Status:   r.URL.Query().Get("status"),
+	})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	claims := make([]data.TeamClaim, 0, len(rows))
+	for _, row := range rows {
+		claims = append(claims, teamClaimFromRow(row))
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"claims": claims}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// showTeamClaimHandler shows a claim with every decision made on it.
+func (app *application) showTeamClaimHandler(w http.ResponseWriter, r *http.Request) {
+	claim, err := app.claimInLeague(r)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		app.notFoundResponse(w, r)
+		return
+	case err != nil:
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	rows, err := app.queries.ListTeamClaimEvents(r.Context(), claim.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	history := make([]data.TeamClaimEvent, 0, len(rows))
+	for _, row := range rows {
+		event := data.TeamClaimEvent{
+			Status:    row.Status,
+			ActorName: row.ActorName.String,
+			Note:      row.Note,
+			CreatedAt: row.CreatedAt,
+		}
+		if row.ActorID.Valid {
+			event.ActorID = &row.ActorID.Int64
+		}
+		history = append(history, event)
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"claim": teamClaimFromModel(claim), "history": history}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// decideTeamClaimHandler approves or rejects a pending claim, or revokes an
+// approved one.
+func (app *application) decideTeamClaimHandler(w http.ResponseWriter, r *http.Request) {
+	user := contextGetUser(r)
+
+	var input struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}
+	if err := app.readJSON(w, r, &input); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if data.ValidateClaimDecision(v, input.Status, input.Note); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	claim, err := app.claimInLeague(r)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		app.notFoundResponse(w, r)
+		return
+	case err != nil:
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if claim.UserID == user.ID {
+		app.notPermittedResponse(w, r)
+		return
+	}
+
+	decided, err := app.decideTeamClaim(r.Context(), claim, user.ID, input.Status, input.Note)
+	switch {
+	case errors.Is(err, errClaimNotOpen):
+		app.claimConflictResponse(w, r)
+		return
+	case err != nil:
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"claim": decided}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// listMyClaimsHandler lists the claims made by the user, in every league.
+func (app *application) listMyClaimsHandler(w http.ResponseWriter, r *http.Request) {
+	user := contextGetUser(r)
+
+	claims, err := app.userTeamClaims(r.Context(), user.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"claims": claims}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// withdrawClaimHandler withdraws one of the user's own pending or approved claims.
+func (app *application) withdrawClaimHandler(w http.ResponseWriter, r *http.Request) {
+	user := contextGetUser(r)
+
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	claim, err := app.withdrawTeamClaim(r.Context(), user.ID, id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		app.notFoundResponse(w, r)
+		return
+	case errors.Is(err, errClaimNotOpen):
+		app.claimConflictResponse(w, r)
+		return
+	case err != nil:
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"claim": claim}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *application) userTeamClaims(ctx context.Context, userID int64) ([]data.TeamClaim, error) {
+	rows, err := app.queries.ListUserTeamClaims(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	claims := make([]data.TeamClaim, 0, len(rows))
+	for _, row := range rows {
+		claims = append(claims, teamClaimFromModel(row))
+	}
+	return claims, nil
+}
+
+// withdrawTeamClaim withdraws the claim with claimID if userID made it. It returns
+// sql.ErrNoRows for anyone else's claim.
+func (app *application) withdrawTeamClaim(ctx context.Context, userID, claimID int64) (*data.TeamClaim, error) {
+	claim, err := app.queries.GetTeamClaim(ctx, claimID)
+	if err != nil {
+		return nil, err
+	}
+	if claim.UserID != userID {
+		return nil, sql.ErrNoRows
+	}
+	return app.setTeamClaimStatus(ctx, claimID, data.ClaimWithdrawn, data.OpenClaimStatuses, userID, "")
+}
+
+// dashboardTeamsHandler renders the "my teams" panel: the teams the user's
+// approved claims cover, their claims, and a form to claim another owner.
+func (app *application) dashboardTeamsHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := app.sessionUser(r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.renderMyTeams(w, r, user.ID, nil)
+}
+
+// dashboardCreateClaimHandler claims the owner picked in the dashboard form. The
+// form's target is "<ESPN league ID>:<owner>".
+func (app *application) dashboardCreateClaimHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	user, err := app.sessionUser(r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	league, owner, _ := strings.Cut(r.PostForm.Get("target"), ":")
+	leagueID, err := strconv.ParseInt(league, 10, 32)
+	if err != nil {
+		v.AddError("owner", "pick an owner to claim")
+	} else {
+		input := teamClaimInput{Owner: owner, Message: r.PostForm.Get("message")}
+		if _, err := app.createTeamClaim(r.Context(), user.ID, int32(leagueID), input, v); err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	app.renderMyTeams(w, r, user.ID, v.Errors)
+}
+
+func (app *application) dashboardWithdrawClaimHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user, err := app.sessionUser(r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	_, err = app.withdrawTeamClaim(r.Context(), user.ID, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) && !errors.Is(err, errClaimNotOpen) {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.renderMyTeams(w, r, user.ID, nil)
+}
+
+func (app *application) renderMyTeams(w http.ResponseWriter, r *http.Request, userID int64, errs map[string]string) {
+	teams, err := app.queries.ListClaimedTeams(r.Context(), userID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	claims, err := app.userTeamClaims(r.Context(), userID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	owners, err := app.queries.ListLeagueOwners(r.Context())
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = templates.MyTeams(teams, claims, owners, errs).Render(r.Context(), w)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// dashboardClaimReviewsHandler renders the pending claims the user can decide on
+// as a commissioner or admin. It renders an empty panel for everyone else.
+func (app *application) dashboardClaimReviewsHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := app.sessionUser(r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.renderClaimReviews(w, r, user.ID)
+}
+
+// dashboardDecideClaimHandler records a decision made from the review panel and
+// re-renders it.
+func (app *application) dashboardDecideClaimHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	user, err := app.sessionUser(r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	status, note := r.PostForm.Get("status"), r.PostForm.Get("note")
+	v := validator.New()
+	if data.ValidateClaimDecision(v, status, note); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	claim, err := app.queries.GetTeamClaim(r.Context(), id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		app.notFoundResponse(w, r)
+		return
+	case err != nil:
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	role, err := app.leagueRole(r.Context(), user.ID, claim.LeagueID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if !data.RoleAtLeast(role, data.RoleCommissioner) {
+		app.notPermittedResponse(w, r)
+		return
+	}
+
+	_, err = app.decideTeamClaim(r.Context(), claim, user.ID, status, note)
+	if err != nil && !errors.Is(err, errClaimNotOpen) {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.renderClaimReviews(w, r, user.ID)
+}
+
+func (app *application) renderClaimReviews(w http.ResponseWriter, r *http.Request, userID int64) {
+	admin, err := app.isSiteAdmin(r.Context(), userID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	rows, err := app.queries.ListReviewableTeamClaims(r.Context(), db.ListReviewableTeamClaimsParams{UserID: userID, AllLeagues: admin})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	claims := make([]data.TeamClaim, 0, len(rows))
+	for _, row := range rows {
+		claims = append(claims, teamClaimFromRow(db.ListLeagueTeamClaimsRow(row)))
+	}
+
+	err = templates.ClaimReviews(claims).Render(r.Context(), w)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
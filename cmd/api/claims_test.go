@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/layer8s/home-dashboard-app/internal/data"
+	"github.com/layer8s/home-dashboard-app/internal/db"
+	"github.com/layer8s/home-dashboard-app/internal/validator"
+	"github.com/lib/pq"
+)
+
+var teamClaimColumns = []string{"id", "user_id", "league_id", "owner", "team_id", "status", "message", "decided_by", "created_at", "updated_at"}
+
+// teamClaimRow is a row for claim id on the owner "Bob" in league 100, made by
+// userID.
+func teamClaimRow(id, userID int64, status string) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows(teamClaimColumns).AddRow(id, userID, 100, "Bob", nil, status, "", nil, now, now)
+}
+
+func TestCreateTeamClaimDuplicate(t *testing.T) {
+	tests := []struct {
+		name  string
+		input teamClaimInput
+		field string
+	}{
+		{"by owner", teamClaimInput{Owner: "Bob"}, "owner"},
+		{"by team", teamClaimInput{TeamID: 4}, "team_id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, _, mock := newTestApplication(t)
+			const userID = 8
+
+			mock.ExpectQuery("-- name: TeamClaimTargetExists").
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			mock.ExpectBegin()
+			mock.ExpectQuery("-- name: InsertTeamClaim").
+				WillReturnError(&pq.Error{Code: "23505"})
+			mock.ExpectRollback()
+
+			v := validator.New()
+			claim, err := app.createTeamClaim(context.Background(), userID, 100, tt.input, v)
+			if err != nil || claim != nil {
+				t.Fatalf("got %v, %v, want a validation error", claim, err)
+			}
+			if _, ok := v.Errors[tt.field]; !ok || len(v.Errors) != 1 {
+				t.Errorf("errors %v, want one for %s", v.Errors, tt.field)
+			}
+		})
+	}
+}
+
+func TestDecideTeamClaim(t *testing.T) {
+	app, _, mock := newTestApplication(t)
+	const claimID, claimantID, commissionerID = 12, 8, 2
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("-- name: UpdateTeamClaimStatus").
+		WithArgs(data.ClaimApproved, commissionerID, claimID, sqlmock.AnyArg()).
+		WillReturnRows(teamClaimRow(claimID, claimantID, data.ClaimApproved))
+	mock.ExpectExec("-- name: InsertTeamClaimEvent").
+		WithArgs(claimID, data.ClaimApproved, commissionerID, "welcome").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	claim := db.TeamClaim{ID: claimID, UserID: claimantID, LeagueID: 100, Status: data.ClaimPending}
+	decided, err := app.decideTeamClaim(context.Background(), claim, commissionerID, data.ClaimApproved, "welcome")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decided.Status != data.ClaimApproved {
+		t.Errorf("status %q, want %q", decided.Status, data.ClaimApproved)
+	}
+}
+
+func TestDecideOwnTeamClaim(t *testing.T) {
+	app, _, _ := newTestApplication(t)
+	const userID = 8
+
+	// A commissioner's own claim is left for another to decide, without touching
+	// the database.
+	claim := db.TeamClaim{ID: 12, UserID: userID, LeagueID: 100, Status: data.ClaimPending}
+	if _, err := app.decideTeamClaim(context.Background(), claim, userID, data.ClaimApproved, ""); !errors.Is(err, errClaimNotOpen) {
+		t.Errorf("got %v, want errClaimNotOpen", err)
+	}
+}
+
+func TestWithdrawClaimOfAnotherUser(t *testing.T) {
+	app, _, mock := newTestApplication(t)
+	const claimID, claimantID, userID = 12, 8, 9
+
+	mock.ExpectQuery("-- name: GetTeamClaim").
+		WithArgs(claimID).
+		WillReturnRows(teamClaimRow(claimID, claimantID, data.ClaimPending))
+
+	r := httptest.NewRequest(http.MethodDelete, "/v1/claims/12", nil)
+	r = r.WithContext(context.WithValue(r.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "12"}}))
+	r = app.contextSetUser(r, &authenticatedUser{ID: userID})
+	w := httptest.NewRecorder()
+	app.withdrawClaimHandler(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestLeagueRoleFromApprovedClaim(t *testing.T) {
+	const userID = 8
+
+	tests := []struct {
+		name    string
+		role    string
+		claimed bool
+		want    string
+	}{
+		{"approved claim", "", true, data.RoleMember},
+		{"viewer with an approved claim", data.RoleViewer, true, data.RoleMember},
+		{"viewer", data.RoleViewer, false, data.RoleViewer},
+		{"no role", "", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, _, mock := newTestApplication(t)
+
+			roles := sqlmock.NewRows([]string{"role"})
+			if tt.role != "" {
+				roles.AddRow(tt.role)
+			}
+			mock.ExpectQuery("-- name: GetLeagueRole").
+				WithArgs(userID, 100).
+				WillReturnRows(roles)
+			mock.ExpectQuery("-- name: HasApprovedTeamClaim").
+				WithArgs(userID, 100).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(tt.claimed))
+
+			role, err := app.leagueRole(context.Background(), userID, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if role != tt.want {
+				t.Errorf("role %q, want %q", role, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithdrawTeamClaimNotOpen(t *testing.T) {
+	app, _, mock := newTestApplication(t)
+	const claimID, userID = 12, 8
+
+	mock.ExpectQuery("-- name: GetTeamClaim").
+		WithArgs(claimID).
+		WillReturnRows(teamClaimRow(claimID, userID, data.ClaimRejected))
+	mock.ExpectBegin()
+	mock.ExpectQuery("-- name: UpdateTeamClaimStatus").
+		WithArgs(data.ClaimWithdrawn, userID, claimID, sqlmock.AnyArg()).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	if _, err := app.withdrawTeamClaim(context.Background(), userID, claimID); !errors.Is(err, errClaimNotOpen) {
+		t.Errorf("got %v, want errClaimNotOpen", err)
+	}
+}
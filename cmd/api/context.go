@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/layer8s/home-dashboard-app/internal/db"
+)
+
+type contextKey string
+
+const (
+	requestIDContextKey = contextKey("requestID")
+	loggerContextKey    = contextKey("logger")
+	userContextKey      = contextKey("user")
+	leagueContextKey    = contextKey("league")
+)
+
+// authenticatedUser is who a request was made by, whether it presented an API
+// token or came from a signed-in browser session.
+type authenticatedUser struct {
+	ID   int64
+	Name string
+	// TokenID and Scope are only set for requests made with an API token.
+	TokenID int64
+	Scope   string
+}
+
+// contextSetRequestID returns a copy of r whose context carries the request ID and
+// a logger that includes it in every line.
+func (app *application) contextSetRequestID(r *http.Request, id string) *http.Request {
+	ctx := context.WithValue(r.Context(), requestIDContextKey, id)
+	ctx = context.WithValue(ctx, loggerContextKey, app.logger.With("request_id", id))
+	return r.WithContext(ctx)
+}
+
+// contextGetRequestID returns the ID of the request ctx belongs to, or "" outside
+// of a request.
+func contextGetRequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+// loggerFromContext returns the request-scoped logger carried by ctx, falling back to
+// app.logger for work that didn't start with a request.
+func (app *application) loggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerContextKey).(*slog.Logger); ok {
+		return logger
+	}
+	return app.logger
+}
+
+// requestLogger returns the logger for r, tagged with its request ID.
+func (app *application) requestLogger(r *http.Request) *slog.Logger {
+	return app.loggerFromContext(r.Context())
+}
+
+// contextSetUser returns a copy of r whose context carries the user it was made by.
+func (app *application) contextSetUser(r *http.Request, user *authenticatedUser) *http.Request {
+	ctx := context.WithValue(r.Context(), userContextKey, user)
+	return r.WithContext(ctx)
+}
+
+// contextGetUser returns the user r was made by, or nil if it hasn't been
+// authenticated.
+func contextGetUser(r *http.Request) *authenticatedUser {
+	user, _ := r.Context().Value(userContextKey).(*authenticatedUser)
+	return user
+}
+
+// leagueAccess is the league a request under /v1/leagues/:id is about and the
+// role its user holds there, as checked by requireLeagueRole.
+type leagueAccess struct {
+	League db.League
+	Role   string
+}
+
+func (app *application) contextSetLeagueAccess(r *http.Request, access *leagueAccess) *http.Request {
+	ctx := context.WithValue(r.Context(), leagueContextKey, access)
+	return r.WithContext(ctx)
+}
+
+// contextGetLeagueAccess returns the league and role requireLeagueRole checked
+// for r, or nil on routes it doesn't guard.
+func contextGetLeagueAccess(r *http.Request) *leagueAccess {
+	access, _ := r.Context().Value(leagueContextKey).(*leagueAccess)
+	return access
+}
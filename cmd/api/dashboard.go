@@ -2,6 +2,7 @@ package main
 
 import (
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/layer8s/home-dashboard-app/internal/db"
@@ -92,9 +93,29 @@ func (app *application) dashboardHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	identities, err := app.queries.ListUserIdentities(r.Context(), user.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	// Offer to link the available providers the user hasn't linked yet.
+	var linkable []string
+	for _, name := range app.authManager.Available() {
+		if !slices.ContainsFunc(identities, func(i db.UserIdentity) bool { return i.Provider == name }) {
+			linkable = append(linkable, name)
+		}
+	}
+
+	tokens, err := app.tokens.ListAPITokens(r.Context(), user.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
 	// Render the full dashboard inside the base layout
 	err = templates.Base(
-		templates.Dashboard(namePtr, emailPtr, providerPtr, subPtr),
+		templates.Dashboard(namePtr, emailPtr, providerPtr, subPtr, identities, linkable, tokens),
 	).Render(r.Context(), w)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 )
 
 func (app *application) failedValidationResponse(w http.ResponseWriter, r *http.Request, errors map[string]string) {
@@ -18,7 +19,7 @@ func (app *application) logError(r *http.Request, err error) {
 		method = r.Method
 		uri    = r.URL.RequestURI()
 	)
-	app.logger.Error(err.Error(), "method", method, "uri", uri)
+	app.requestLogger(r).Error(err.Error(), "method", method, "uri", uri)
 
 }
 
@@ -48,3 +49,62 @@ func (app *application) methodNotAllowedResponse(w http.ResponseWriter, r *http.
 	message := fmt.Sprintf("the %s method is not supported for this resource", r.Method)
 	app.errorResponse(w, r, http.StatusMethodNotAllowed, message)
 }
+
+func (app *application) notAcceptableResponse(w http.ResponseWriter, r *http.Request) {
+	message := "the requested format is not supported; use json, csv or ndjson"
+	app.errorResponse(w, r, http.StatusNotAcceptable, message)
+}
+
+func (app *application) rateLimitExceededResponse(w http.ResponseWriter, r *http.Request) {
+	message := "rate limit exceeded"
+	app.errorResponse(w, r, http.StatusTooManyRequests, message)
+}
+
+func (app *application) providerUnavailableResponse(w http.ResponseWriter, r *http.Request, provider string, err error) {
+	app.requestLogger(r).Warn("auth provider unavailable", "provider", provider, "error", err)
+	w.Header().Set("Retry-After", strconv.Itoa(int(providerRetryMin.Seconds())))
+	message := fmt.Sprintf("signing in with %s is temporarily unavailable; please try again shortly", provider)
+	app.errorResponse(w, r, http.StatusServiceUnavailable, message)
+}
+
+func (app *application) identityConflictResponse(w http.ResponseWriter, r *http.Request, provider string) {
+	message := fmt.Sprintf("this %s account is already linked to another user", provider)
+	app.errorResponse(w, r, http.StatusConflict, message)
+}
+
+func (app *application) authenticationRequiredResponse(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	message := "you must be signed in or present an API token to access this resource"
+	app.errorResponse(w, r, http.StatusUnauthorized, message)
+}
+
+func (app *application) sessionReadOnlyResponse(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	message := "a browser session can only read this resource; present an API token to change it"
+	app.errorResponse(w, r, http.StatusUnauthorized, message)
+}
+
+func (app *application) readOnlyTokenResponse(w http.ResponseWriter, r *http.Request) {
+	message := "this API token is read-only"
+	app.errorResponse(w, r, http.StatusForbidden, message)
+}
+
+func (app *application) invalidCredentialsResponse(w http.ResponseWriter, r *http.Request) {
+	message := "invalid authentication credentials"
+	app.errorResponse(w, r, http.StatusUnauthorized, message)
+}
+
+func (app *application) inactiveAccountResponse(w http.ResponseWriter, r *http.Request) {
+	message := "your user account must be activated to access this resource"
+	app.errorResponse(w, r, http.StatusForbidden, message)
+}
+
+func (app *application) notPermittedResponse(w http.ResponseWriter, r *http.Request) {
+	message := "your user account doesn't have the necessary permissions to access this resource"
+	app.errorResponse(w, r, http.StatusForbidden, message)
+}
+
+func (app *application) claimConflictResponse(w http.ResponseWriter, r *http.Request) {
+	message := "this claim has already been decided or withdrawn"
+	app.errorResponse(w, r, http.StatusConflict, message)
+}
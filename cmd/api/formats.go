@@ -0,0 +1,287 @@
+package main
+
+import (
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Response formats supported by the read endpoints. JSON remains the default; CSV
+// and NDJSON are offered for spreadsheets and line-oriented tooling.
+const (
+	formatJSON   = "json"
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+)
+
+var errUnsupportedFormat = errors.New("unsupported response format")
+
+var formatContentTypes = map[string]string{
+	formatJSON:   "application/json",
+	formatCSV:    "text/csv; charset=utf-8",
+	formatNDJSON: "application/x-ndjson",
+}
+
+var mediaTypeFormats = map[string]string{
+	"application/json":     formatJSON,
+	"application/*":        formatJSON,
+	"*/*":                  formatJSON,
+	"text/csv":             formatCSV,
+	"application/x-ndjson": formatNDJSON,
+	"application/ndjson":   formatNDJSON,
+}
+
+// negotiateFormat picks the response format for a request. An explicit ?format=
+// query string value always wins; otherwise the Accept header is consulted, and a
+// request without one gets JSON.
+func (app *application) negotiateFormat(r *http.Request) (string, error) {
+	if format := strings.ToLower(r.URL.Query().Get("format")); format != "" {
+		if _, ok := formatContentTypes[format]; !ok {
+			return "", errUnsupportedFormat
+		}
+		return format, nil
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return formatJSON, nil
+	}
+
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, q := parseAcceptPart(part)
+		format, ok := mediaTypeFormats[mediaType]
+		if !ok || q <= bestQ {
+			continue
+		}
+		best, bestQ = format, q
+	}
+
+	if best == "" {
+		return "", errUnsupportedFormat
+	}
+	return best, nil
+}
+
+// parseAcceptPart splits a single Accept header entry such as "text/csv;q=0.8" into
+// its media type and quality value.
+func parseAcceptPart(part string) (string, float64) {
+	fields := strings.Split(part, ";")
+	mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+	q := 1.0
+	for _, param := range fields[1:] {
+		key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if !found || strings.TrimSpace(key) != "q" {
+			continue
+		}
+		if f, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			q = f
+		}
+	}
+	return mediaType, q
+}
+
+// writeFormatted writes a single resource in the negotiated format. JSON responses
+// keep the usual envelope; CSV and NDJSON responses contain just the record and are
+// sent as a download named after filename.
+func (app *application) writeFormatted(w http.ResponseWriter, status int, format, key, filename string, record any, headers http.Header) error {
+	if format == formatJSON {
+		return app.writeJSON(w, status, envelope{key: record}, headers)
+	}
+
+	for name, value := range headers {
+		w.Header()[name] = value
+	}
+	setDownloadHeaders(w, format, filename)
+	w.WriteHeader(status)
+
+	enc := newRowEncoder(w, format)
+	if err := enc.Encode(record); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+// streamRows writes a listing as CSV or NDJSON, one row at a time. The each function
+// is expected to call emit for every row as it is read from the database. sample is
+// a value of the row type, which gives an empty CSV listing its header line. If each
+// fails before any row has been written an error is returned so that the caller can
+// still send a normal error response; after that point the error is only logged,
+// since the status line has already gone out.
+func (app *application) streamRows(w http.ResponseWriter, r *http.Request, format, filename string, sample any, each func(emit func(any) error) error) error {
+	enc := newRowEncoder(w, format)
+	flusher, _ := w.(http.Flusher)
+
+	written := 0
+	err := each(func(row any) error {
+		if written == 0 {
+			setDownloadHeaders(w, format, filename)
+			w.WriteHeader(http.StatusOK)
+		}
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+		written++
+
+		// Push rows to the client in batches so that memory use stays flat however
+		// large the result set is.
+		if written%100 == 0 {
+			if err := enc.Flush(); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return nil
+	})
+
+	if err != nil && written == 0 {
+		return err
+	}
+	if err != nil {
+		app.logError(r, fmt.Errorf("streaming %s response: %w", format, err))
+		return nil
+	}
+
+	// An empty listing still gets the download headers and, for CSV, the header
+	// line.
+	if written == 0 {
+		setDownloadHeaders(w, format, filename)
+		w.WriteHeader(http.StatusOK)
+		if err := enc.Header(sample); err != nil {
+			return err
+		}
+	}
+	return enc.Flush()
+}
+
+func setDownloadHeaders(w http.ResponseWriter, format, filename string) {
+	w.Header().Set("Content-Type", formatContentTypes[format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+"."+format))
+}
+
+type rowEncoder interface {
+	Encode(row any) error
+	// Header writes whatever precedes the rows, taking the columns from row, unless
+	// it has already been written.
+	Header(row any) error
+	Flush() error
+}
+
+func newRowEncoder(w http.ResponseWriter, format string) rowEncoder {
+	if format == formatCSV {
+		return &csvRowEncoder{w: csv.NewWriter(w)}
+	}
+	return &ndjsonRowEncoder{enc: json.NewEncoder(w)}
+}
+
+type ndjsonRowEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonRowEncoder) Encode(row any) error {
+	return e.enc.Encode(row)
+}
+
+func (e *ndjsonRowEncoder) Header(row any) error {
+	return nil
+}
+
+func (e *ndjsonRowEncoder) Flush() error {
+	return nil
+}
+
+// csvRowEncoder writes the header line, taken from the JSON field names of the first
+// row, before the first record.
+type csvRowEncoder struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func (e *csvRowEncoder) Encode(row any) error {
+	if err := e.Header(row); err != nil {
+		return err
+	}
+	_, record := flattenRecord(row)
+	return e.w.Write(record)
+}
+
+func (e *csvRowEncoder) Header(row any) error {
+	if e.wroteHeader {
+		return nil
+	}
+	header, _ := flattenRecord(row)
+	e.wroteHeader = true
+	return e.w.Write(header)
+}
+
+func (e *csvRowEncoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// flattenRecord turns a struct into CSV column names and values. Column names follow
+// the json struct tags so that both formats describe fields identically. Nullable
+// sql.Null* values are unwrapped to their underlying value, or an empty cell when
+// they are NULL.
+func flattenRecord(row any) ([]string, []string) {
+	rv := reflect.Indirect(reflect.ValueOf(row))
+	if rv.Kind() != reflect.Struct {
+		return []string{"value"}, []string{formatCSVValue(rv)}
+	}
+
+	rt := rv.Type()
+	header := make([]string, 0, rt.NumField())
+	record := make([]string, 0, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		header = append(header, name)
+		record = append(record, formatCSVValue(rv.Field(i)))
+	}
+	return header, record
+}
+
+func formatCSVValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		return formatCSVValue(v.Elem())
+	}
+
+	if valuer, ok := v.Interface().(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil || value == nil {
+			return ""
+		}
+		return formatCSVValue(reflect.ValueOf(value))
+	}
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.Format(time.RFC3339)
+	case []byte:
+		return string(value)
+	}
+	return fmt.Sprint(v.Interface())
+}
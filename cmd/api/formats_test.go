@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	app := &application{}
+	tests := []struct {
+		name   string
+		query  string
+		accept string
+		want   string
+		err    bool
+	}{
+		{name: "default", want: formatJSON},
+		{name: "query", query: "csv", want: formatCSV},
+		{name: "query upper case", query: "CSV", want: formatCSV},
+		{name: "query unknown", query: "xml", err: true},
+		{name: "accept", accept: "text/csv", want: formatCSV},
+		{name: "accept quality", accept: "text/csv;q=0.5, application/x-ndjson", want: formatNDJSON},
+		{name: "accept unknown", accept: "text/html", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/v1/leagues?format="+tt.query, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			got, err := app.negotiateFormat(r)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("got %q, want an error", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("got %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamRowsEmptyCSV(t *testing.T) {
+	type row struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	app := &application{}
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/v1/leagues?format=csv", nil)
+
+	err := app.streamRows(w, r, formatCSV, "leagues", row{}, func(emit func(any) error) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := w.Body.String(), "id,name\n"; got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+	if got := w.Header().Get("Content-Type"); got != formatContentTypes[formatCSV] {
+		t.Errorf("Content-Type = %q", got)
+	}
+}
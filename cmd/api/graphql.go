@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/layer8s/home-dashboard-app/internal/graph"
+	"github.com/layer8s/home-dashboard-app/internal/validator"
+)
+
+// graphqlHandler executes a read-only GraphQL query against the archive. Queries
+// can be sent as a GET request with query, operationName and variables in the query
+// string, or as a POST request with the same fields in a JSON body.
+func (app *application) graphqlHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Query         string         `json:"query"`
+		OperationName string         `json:"operationName"`
+		Variables     map[string]any `json:"variables"`
+	}
+
+	v := validator.New()
+
+	if r.Method == http.MethodPost {
+		err := app.readJSON(w, r, &input)
+		if err != nil {
+			app.badRequestResponse(w, r, err)
+			return
+		}
+	} else {
+		qs := r.URL.Query()
+		input.Query = app.readString(qs, "query", "")
+		input.OperationName = app.readString(qs, "operationName", "")
+		if variables := qs.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &input.Variables); err != nil {
+				v.AddError("variables", "must be a JSON object")
+			}
+		}
+	}
+
+	v.Check(input.Query != "", "query", "must be provided")
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(input.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		app.writeGraphQLErrors(w, r, gqlerrors.FormatErrors(err))
+		return
+	}
+
+	validation := graphql.ValidateDocument(&app.graphqlSchema, doc, nil)
+	if !validation.IsValid {
+		app.writeGraphQLErrors(w, r, validation.Errors)
+		return
+	}
+
+	// Mutations aren't part of the schema, but refuse them explicitly on GET so the
+	// endpoint stays safe to call from links and caches.
+	if r.Method == http.MethodGet && hasMutation(doc) {
+		app.methodNotAllowedResponse(w, r)
+		return
+	}
+
+	err = graph.CheckLimits(app.graphqlSchema, doc, graph.Limits{
+		MaxDepth:      app.config.GraphQL.MaxDepth,
+		MaxComplexity: app.config.GraphQL.MaxComplexity,
+	})
+	if err != nil {
+		app.writeGraphQLErrors(w, r, gqlerrors.FormatErrors(err))
+		return
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        app.graphqlSchema,
+		AST:           doc,
+		OperationName: input.OperationName,
+		Args:          input.Variables,
+		Context:       graph.ContextWithLoaders(r.Context(), graph.NewLoaders(app.queries)),
+	})
+
+	env := envelope{"data": result.Data}
+	if result.HasErrors() {
+		env["errors"] = result.Errors
+	}
+
+	err = app.writeJSON(w, http.StatusOK, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// writeGraphQLErrors sends errors raised before execution started in the response
+// shape GraphQL clients expect.
+func (app *application) writeGraphQLErrors(w http.ResponseWriter, r *http.Request, errs []gqlerrors.FormattedError) {
+	err := app.writeJSON(w, http.StatusBadRequest, envelope{"errors": errs}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func hasMutation(doc *ast.Document) bool {
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok && op.Operation == ast.OperationTypeMutation {
+			return true
+		}
+	}
+	return false
+}
@@ -1,14 +1,23 @@
 package main
 
 import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 func (app *application) healthcheckHandler(w http.ResponseWriter, r *http.Request) {
 	env := envelope{
 		"status": "available",
 		"system_info": map[string]string{
-			"environment": app.config.env,
+			"environment": app.config.Env,
 			"version":     version,
 		},
 	}
@@ -18,3 +27,137 @@ func (app *application) healthcheckHandler(w http.ResponseWriter, r *http.Reques
 		app.serverErrorResponse(w, r, err)
 	}
 }
+
+// healthCheckTimeout bounds each readiness check so one hung dependency can't stall
+// the probe past the orchestrator's own timeout.
+const healthCheckTimeout = 2 * time.Second
+
+// healthCheck is the outcome of checking a single dependency.
+type healthCheck struct {
+	Status    string  `json:"status"`
+	Required  bool    `json:"required"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// liveHandler reports that the process is up and serving requests. It checks no
+// dependencies, so a database outage never gets the process restarted.
+func (app *application) liveHandler(w http.ResponseWriter, r *http.Request) {
+	err := app.writeJSON(w, http.StatusOK, envelope{"status": "alive"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// readyHandler checks Postgres, Redis and the discovery endpoint of every
+// registered OIDC provider concurrently, and lists the state of each configured
+// provider. It responds with 503 if any required check fails; failures of optional
+// checks are reported but don't change the status.
+func (app *application) readyHandler(w http.ResponseWriter, r *http.Request) {
+	checks := map[string]func(context.Context) error{
+		"db": func(ctx context.Context) error {
+			return app.db.PingContext(ctx)
+		},
+		"redis": func(ctx context.Context) error {
+			return app.redisClient.Ping(ctx).Err()
+		},
+	}
+
+	// A provider that hasn't been set up fails with the error from its last
+	// attempt; once discovered, its discovery document is checked each time.
+	providers := app.authManager.Status()
+	for _, provider := range providers {
+		switch {
+		case provider.Status != "available":
+			failure := cmp.Or(provider.Error, "provider "+provider.Status)
+			checks["oidc:"+provider.Name] = func(context.Context) error {
+				return errors.New(failure)
+			}
+		case provider.discovered:
+			issuer := provider.issuer
+			checks["oidc:"+provider.Name] = func(ctx context.Context) error {
+				return pingOIDCDiscovery(ctx, issuer)
+			}
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]healthCheck, len(checks))
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check(ctx)
+			result := healthCheck{
+				Status:    "up",
+				Required:  !app.healthCheckOptional(name),
+				LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				result.Status = "down"
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	status, code := "ready", http.StatusOK
+	var failed []string
+	for name, result := range results {
+		if result.Status == "down" && result.Required {
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		status, code = "unavailable", http.StatusServiceUnavailable
+		app.requestLogger(r).Warn("readiness check failed", "checks", strings.Join(failed, ","))
+	}
+
+	err := app.writeJSON(w, code, envelope{"status": status, "checks": results, "providers": providers}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// healthCheckOptional reports whether a failing check should be tolerated. "oidc"
+// in the configuration covers every provider.
+func (app *application) healthCheckOptional(name string) bool {
+	optional := app.config.Health.Optional
+	if slices.Contains(optional, name) {
+		return true
+	}
+	return strings.HasPrefix(name, "oidc:") && slices.Contains(optional, "oidc")
+}
+
+// pingOIDCDiscovery fetches the provider's OpenID configuration document.
+func pingOIDCDiscovery(ctx context.Context, issuer string) error {
+	url := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := tracedHTTPClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("discovery endpoint returned %s", res.Status)
+	}
+	return nil
+}
@@ -1,22 +1,22 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
 	"io"
-	"log"
+	"mime"
 	"net/http"
 	"net/url"
-	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
-	"github.com/joho/godotenv"
 	"github.com/julienschmidt/httprouter"
+	"github.com/layer8s/home-dashboard-app/internal/db"
 	"github.com/layer8s/home-dashboard-app/internal/validator"
 )
 
@@ -31,6 +31,17 @@ func (app *application) readIDParam(r *http.Request) (int64, error) {
 	return id, nil
 }
 
+// readNestedIDParam reads an ID parameter of routes nested under another ID, such
+// as :user_id in /v1/leagues/:id/roles/:user_id.
+func (app *application) readNestedIDParam(r *http.Request, name string) (int64, error) {
+	params := httprouter.ParamsFromContext(r.Context())
+	id, err := strconv.ParseInt(params.ByName(name), 10, 64)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("invalid %s parameter", name)
+	}
+	return id, nil
+}
+
 func (app *application) readProviderParam(r *http.Request) (string, error) {
 	params := httprouter.ParamsFromContext(r.Context())
 	provider := params.ByName("provider")
@@ -42,6 +53,12 @@ func (app *application) readProviderParam(r *http.Request) (string, error) {
 }
 
 func (app *application) readJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	// Forms and text/plain bodies can be posted from any site; JSON can't be
+	// without the browser asking first, so only JSON is accepted.
+	if !isJSONRequest(r) {
+		return errors.New("body must be JSON, sent with Content-Type: application/json")
+	}
+
 	maxBytes := 1_048_576
 	r.Body = http.MaxBytesReader(w, r.Body, int64(maxBytes))
 	dec := json.NewDecoder(r.Body)
@@ -98,6 +115,13 @@ func (app *application) writeJSON(w http.ResponseWriter, status int, data envelo
 	return nil
 }
 
+// isJSONRequest reports whether r carries a JSON body rather than a form, for
+// endpoints that serve both the login page and API clients.
+func isJSONRequest(r *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == "application/json"
+}
+
 func (app *application) readString(qs url.Values, key string, defaultValue string) string {
 	s := qs.Get(key)
 	if s == "" {
@@ -139,81 +163,40 @@ func (app *application) readIntQuery(qs url.Values, key string, v *validator.Val
 	return int32(i) // Return the valid int32 value
 }
 
-func loadEnvironment() (int, string, string, int, int, time.Duration, string, string, string, string, int) {
-	if err := godotenv.Load(); err != nil {
-		log.Fatalf("Failed to load the env vars: %v", err)
-	}
-	port, err := strconv.Atoi(os.Getenv("PORT"))
-	if err != nil {
-		log.Fatal("PORT environment variable not set")
-	}
-	env := os.Getenv("ENV")
-	if env == "" {
-		log.Fatal("ENV environment variable not set")
-	}
+// background runs fn in a goroutine tracked by app.wg. fn receives a context that
+// keeps the values of ctx, such as the request logger and trace, but isn't
+// cancelled when the request that started the job finishes.
+func (app *application) background(ctx context.Context, fn func(ctx context.Context)) {
+	ctx = context.WithoutCancel(ctx)
+	logger := app.loggerFromContext(ctx)
 
-	dsn := os.Getenv("DB_URL")
-	if dsn == "" {
-		log.Fatal("DB_URL environment variable not set")
-	}
-	dbMaxOpenConns, err := strconv.Atoi(os.Getenv("DB_MAX_OPEN_CONNS"))
-	if err != nil {
-		log.Fatal("DB_MAX_OPEN_CONNS environment variable not set")
-	}
-	dbMaxIdleConns, err := strconv.Atoi(os.Getenv("DB_MAX_IDLE_CONNS"))
-	if err != nil {
-		log.Fatal("DB_MAX_IDLE_CONNS environment variable not set")
-	}
-	dbMaxIdleTime, err := time.ParseDuration(os.Getenv("DB_MAX_IDLE_TIME"))
-	if err != nil {
-		log.Fatal("DB_MAX_IDLE_TIME environment variable not set")
-	}
-
-	sessionKey := os.Getenv("SESSION_KEY")
-	if sessionKey == "" {
-		log.Fatal("SESSION_KEY must be set")
-	}
-
-	sendGridKey := os.Getenv("SENDGRID_API_KEY")
-	if sendGridKey == "" {
-		log.Fatal("SENDGRID_API_KEY environment variable is required")
-	}
-
-	redisAddr := os.Getenv("REDIS_ADDR")
-	redisPassword := os.Getenv("REDIS_PASSWORD")
-	redisDB, err := strconv.Atoi(os.Getenv("REDIS_DB"))
-	if err != nil {
-		log.Fatal("Failed to convert redisDB to int")
-	}
-	// if redisAddr == "" || redisPassword == "" {
-	// 	log.Fatal("REDIS environment variables are required")
-	// }
-
-	//return the env variables
-	return port, env, dsn, dbMaxOpenConns, dbMaxIdleConns, dbMaxIdleTime, sessionKey, sendGridKey, redisAddr, redisPassword, redisDB
-}
-
-// The background() helper accepts an arbitrary function as a parameter.
-func (app *application) background(fn func()) {
 	// Increment the WaitGroup counter.
 	app.wg.Add(1)
+	app.metrics.backgroundTasks.Inc()
 	go func() {
 		// Recover any panic.
 
 		defer app.wg.Done()
+		defer app.metrics.backgroundTasks.Dec()
 
 		defer func() {
 			if err := recover(); err != nil {
-				app.logger.Error(fmt.Sprintf("%v", err))
+				logger.Error(fmt.Sprintf("%v", err))
 			}
 		}()
 
 		// Execute the arbitrary function that we passed as the parameter.
-		fn()
+		fn(ctx)
 	}()
 }
 
+// txQueries returns the queries run in tx, traced like app.queries.
+func (app *application) txQueries(tx *sql.Tx) *db.Queries {
+	return db.New(db.Traced(tx))
+}
+
 func (app *application) invalidAuthenticationTokenResponse(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
 	message := "invalid or missing authentication token"
 	app.errorResponse(w, r, http.StatusUnauthorized, message)
 }
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/layer8s/home-dashboard-app/internal/data"
+	"github.com/layer8s/home-dashboard-app/internal/db"
+	"github.com/layer8s/home-dashboard-app/internal/validator"
+)
+
+// defaultInvitationDays is how long invitations last when no expiry is asked for.
+const defaultInvitationDays = 7
+
+// inviteSessionKey holds the token of an invitation followed by someone who
+// wasn't signed in yet, so it can be accepted once they are.
+const inviteSessionKey = "invite_token"
+
+func invitationFromModel(inv db.LeagueInvitation) data.Invitation {
+	invitation := data.Invitation{
+		ID:        inv.ID,
+		LeagueID:  inv.LeagueID,
+		Email:     inv.Email,
+		Role:      inv.Role,
+		CreatedAt: inv.CreatedAt,
+		ExpiresAt: inv.ExpiresAt,
+	}
+	if inv.Owner.Valid {
+		invitation.Owner = &inv.Owner.String
+	}
+	if inv.TeamID.Valid {
+		invitation.TeamID = &inv.TeamID.Int32
+	}
+	if inv.InvitedBy.Valid {
+		invitation.InvitedBy = &inv.InvitedBy.Int64
+	}
+	if inv.AcceptedBy.Valid {
+		invitation.AcceptedBy = &inv.AcceptedBy.Int64
+	}
+	invitation.AcceptedAt = timePtr(inv.AcceptedAt)
+	invitation.Status = data.InvitationStatus(invitation.AcceptedAt, timePtr(inv.RevokedAt), inv.ExpiresAt)
+	return invitation
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// createInvitationHandler invites an email address to the league with a role,
+// and optionally as the manager of an owner's or a team's teams. Commissioners
+// can only invite members and viewers.
+func (app *application) createInvitationHandler(w http.ResponseWriter, r *http.Request) {
+	access := contextGetLeagueAccess(r)
+	user := contextGetUser(r)
+
+	var input struct {
+		Email         string `json:"email"`
+		Role          string `json:"role"`
+		Owner         string `json:"owner"`
+		TeamID        int32  `json:"team_id"`
+		ExpiresInDays int    `json:"expires_in_days"`
+	}
+	if err := app.readJSON(w, r, &input); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	input.Email = normalizeEmail(input.Email)
+	input.Owner = strings.TrimSpace(input.Owner)
+	if input.Role == "" {
+		input.Role = data.RoleMember
+	}
+	if input.ExpiresInDays == 0 {
+		input.ExpiresInDays = defaultInvitationDays
+	}
+
+	v := validator.New()
+	if data.ValidateInvitation(v, input.Email, input.Role, input.Owner, input.TeamID, input.ExpiresInDays); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+	if !data.CanAssignRole(access.Role, "", input.Role) {
+		app.notPermittedResponse(w, r)
+		return
+	}
+
+	owner := nullableString(input.Owner)
+	team := sql.NullInt32{Int32: input.TeamID, Valid: input.TeamID != 0}
+	if owner.Valid || team.Valid {
+		exists, err := app.queries.TeamClaimTargetExists(r.Context(), db.TeamClaimTargetExistsParams{
+			LeagueId: access.League.LeagueId,
+			Owners:   owner,
+			TeamId:   input.TeamID,
+		})
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		if !exists {
+			if owner.Valid {
+				v.AddError("owner", "no team in this league has this owner")
+			} else {
+				v.AddError("team_id", "no team in this league has this ID")
+			}
+			app.failedValidationResponse(w, r, v.Errors)
+			return
+		}
+	}
+
+	ttl := time.Duration(input.ExpiresInDays) * 24 * time.Hour
+	token, err := data.NewInvitationToken(ttl)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	row, err := app.queries.InsertLeagueInvitation(r.Context(), db.InsertLeagueInvitationParams{
+		Hash:      token.Hash,
+		LeagueID:  access.League.LeagueId,
+		Email:     input.Email,
+		Role:      input.Role,
+		Owner:     owner,
+		TeamID:    team,
+		InvitedBy: sql.NullInt64{Int64: user.ID, Valid: true},
+		ExpiresAt: token.Expiry,
+	})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	invitation := invitationFromModel(row)
+	app.requestLogger(r).Info("league invitation created", "invitation_id", row.ID, "league_id", row.LeagueID, "role", row.Role, "invited_by", user.ID)
+
+	link := app.config.Auth.BaseCallbackURL + "/v1/invitations/accept?token=" + url.QueryEscape(token.Plaintext)
+	app.background(r.Context(), func(ctx context.Context) {
+		target := ""
+		if invitation.Owner != nil || invitation.TeamID != nil {
+			target = data.TeamClaim{Owner: invitation.Owner, TeamID: invitation.TeamID}.Target()
+		}
+		data := map[string]any{
+			"inviterName":   user.Name,
+			"leagueID":      invitation.LeagueID,
+			"role":          invitation.Role,
+			"team":          target,
+			"link":          link,
+			"expiresInDays": input.ExpiresInDays,
+		}
+
+		err := app.mailer.Send(ctx, invitation.Email, "league_invitation.tmpl", data)
+		if err != nil {
+			app.loggerFromContext(ctx).Error("sending league invitation failed", "invitation_id", invitation.ID, "error", err)
+		}
+	})
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"invitation": invitation}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *application) listInvitationsHandler(w http.ResponseWriter, r *http.Request) {
+	access := contextGetLeagueAccess(r)
+
+	rows, err := app.queries.ListLeagueInvitations(r.Context(), access.League.LeagueId)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	invitations := make([]data.Invitation, 0, len(rows))
+	for _, row := range rows {
+		invitations = append(invitations, invitationFromModel(row))
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"invitations": invitations}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// revokeInvitationHandler revokes an invitation that hasn't been accepted yet.
+func (app *application) revokeInvitationHandler(w http.ResponseWriter, r *http.Request) {
+	access := contextGetLeagueAccess(r)
+
+	id, err := app.readNestedIDParam(r, "invitation_id")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	n, err := app.queries.RevokeLeagueInvitation(r.Context(), db.RevokeLeagueInvitationParams{ID: id, LeagueID: access.League.LeagueId})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if n == 0 {
+		app.notFoundResponse(w, r)
+		return
+	}
+	app.requestLogger(r).Info("league invitation revoked", "invitation_id", id, "league_id", access.League.LeagueId, "revoked_by", contextGetUser(r).ID)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "invitation revoked"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// acceptInvitationHandler is where the emailed link leads. A signed-in user joins
+// the league straight away; anyone else is asked to sign in, with any provider,
+// and joins once they have. Either way the link only works for a user with a
+// verified identity for the address it was sent to, so a forwarded or leaked
+// link is no use to anyone else.
+func (app *application) acceptInvitationHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	session, _ := app.sessionStore.Get(r, "auth-session")
+	if authenticated, _ := session.Values["authenticated"].(bool); authenticated {
+		user, err := app.sessionUser(r)
+		switch {
+		case err == nil:
+			if err := app.acceptInvitations(r.Context(), user.ID, token); err != nil {
+				app.serverErrorResponse(w, r, err)
+				return
+			}
+			http.Redirect(w, r, "/v1/dashboard", http.StatusSeeOther)
+			return
+		case !errors.Is(err, sql.ErrNoRows):
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	session.Values[inviteSessionKey] = token
+	if err := session.Save(r, w); err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// acceptInvitations accepts the invitation with token, if there is one, and every
+// open invitation sent to one of the user's verified email addresses, whichever
+// provider verified it. The token's invitation too must have been sent to one of
+// those addresses. Expired, revoked and already accepted invitations are left
+// alone. An invitation whose sender can no longer grant what it offers, because
+// they've since lost their role in the league, is revoked instead.
+func (app *application) acceptInvitations(ctx context.Context, userID int64, token string) error {
+	tx, err := app.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := app.txQueries(tx)
+	acceptedBy := sql.NullInt64{Int64: userID, Valid: true}
+
+	var accepted []db.LeagueInvitation
+	if token != "" {
+		inv, err := qtx.AcceptLeagueInvitation(ctx, db.AcceptLeagueInvitationParams{UserID: acceptedBy, Hash: data.HashToken(token)})
+		switch {
+		case err == nil:
+			accepted = append(accepted, inv)
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+	}
+
+	byEmail, err := qtx.AcceptLeagueInvitationsByEmail(ctx, acceptedBy)
+	if err != nil {
+		return err
+	}
+	accepted = append(accepted, byEmail...)
+
+	var joined, voided []db.LeagueInvitation
+	for _, inv := range accepted {
+		ok, err := app.inviterMayGrant(ctx, inv)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if err := qtx.VoidLeagueInvitation(ctx, inv.ID); err != nil {
+				return err
+			}
+			voided = append(voided, inv)
+			continue
+		}
+		if err := joinLeague(ctx, qtx, userID, inv); err != nil {
+			return err
+		}
+		joined = append(joined, inv)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	logger := app.loggerFromContext(ctx)
+	for _, inv := range joined {
+		logger.Info("league invitation accepted", "invitation_id", inv.ID, "league_id", inv.LeagueID, "user_id", userID, "role", inv.Role)
+	}
+	for _, inv := range voided {
+		logger.Warn("league invitation revoked on acceptance; its sender can no longer grant it", "invitation_id", inv.ID, "league_id", inv.LeagueID, "user_id", userID, "invited_by", inv.InvitedBy.Int64, "role", inv.Role)
+	}
+	return nil
+}
+
+// inviterMayGrant reports whether whoever sent inv could still send it now. What
+// an invitation offers is checked when it's sent, but roles change while it
+// waits to be accepted.
+func (app *application) inviterMayGrant(ctx context.Context, inv db.LeagueInvitation) (bool, error) {
+	if !inv.InvitedBy.Valid {
+		return false, nil
+	}
+	role, err := app.leagueRole(ctx, inv.InvitedBy.Int64, inv.LeagueID)
+	if err != nil {
+		return false, err
+	}
+	return data.CanAssignRole(role, "", inv.Role), nil
+}
+
+// joinLeague gives userID what inv offers: its role, unless they already hold a
+// higher one, and an approved claim on its owner or team, unless they already
+// have one open. The claim's history credits whoever sent the invitation.
+func joinLeague(ctx context.Context, qtx *db.Queries, userID int64, inv db.LeagueInvitation) error {
+	current, err := qtx.GetLeagueRole(ctx, db.GetLeagueRoleParams{UserID: userID, LeagueID: inv.LeagueID})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if !data.RoleAtLeast(current, inv.Role) {
+		_, err := qtx.UpsertLeagueRole(ctx, db.UpsertLeagueRoleParams{
+			UserID:    userID,
+			LeagueID:  inv.LeagueID,
+			Role:      inv.Role,
+			GrantedBy: inv.InvitedBy,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !inv.Owner.Valid && !inv.TeamID.Valid {
+		return nil
+	}
+	claimID, err := qtx.InsertApprovedTeamClaim(ctx, db.InsertApprovedTeamClaimParams{
+		UserID:    userID,
+		LeagueID:  inv.LeagueID,
+		Owner:     inv.Owner,
+		TeamID:    inv.TeamID,
+		DecidedBy: inv.InvitedBy,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return qtx.InsertTeamClaimEvent(ctx, db.InsertTeamClaimEventParams{
+		ClaimID: claimID,
+		Status:  data.ClaimApproved,
+		ActorID: inv.InvitedBy,
+		Note:    "invitation accepted",
+	})
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/layer8s/home-dashboard-app/internal/data"
+)
+
+var invitationColumns = []string{"id", "hash", "league_id", "email", "role", "owner", "team_id", "invited_by", "created_at", "expires_at", "accepted_at", "accepted_by", "revoked_at"}
+
+// acceptedInvitation is a row for an invitation to join league 100 as a member,
+// sent by invitedBy and just accepted by userID.
+func acceptedInvitation(id int64, invitedBy driver.Value, userID int64) []driver.Value {
+	now := time.Now()
+	return []driver.Value{id, []byte("hash"), 100, "bob@example.com", data.RoleMember, nil, nil, invitedBy, now, now.Add(time.Hour), now, userID, nil}
+}
+
+func TestAcceptInvitations(t *testing.T) {
+	const userID, inviterID = 8, 2
+
+	tests := []struct {
+		name        string
+		invitedBy   driver.Value
+		inviterRole string
+		joins       bool
+	}{
+		{"inviter still a commissioner", int64(inviterID), data.RoleCommissioner, true},
+		{"inviter demoted since", int64(inviterID), data.RoleMember, false},
+		{"inviter deleted since", nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, _, mock := newTestApplication(t)
+
+			mock.ExpectBegin()
+			mock.ExpectQuery("-- name: AcceptLeagueInvitationsByEmail").
+				WithArgs(userID).
+				WillReturnRows(sqlmock.NewRows(invitationColumns).AddRow(acceptedInvitation(30, tt.invitedBy, userID)...))
+			if tt.invitedBy != nil {
+				mock.ExpectQuery("-- name: GetLeagueRole").
+					WithArgs(inviterID, 100).
+					WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(tt.inviterRole))
+			}
+			if tt.joins {
+				mock.ExpectQuery("-- name: GetLeagueRole").
+					WithArgs(userID, 100).
+					WillReturnRows(sqlmock.NewRows([]string{"role"}))
+				mock.ExpectQuery("-- name: UpsertLeagueRole").
+					WithArgs(userID, 100, data.RoleMember, inviterID).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "league_id", "role", "granted_by", "created_at", "updated_at"}).
+						AddRow(userID, 100, data.RoleMember, inviterID, time.Now(), time.Now()))
+			} else {
+				mock.ExpectExec("-- name: VoidLeagueInvitation").
+					WithArgs(30).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			mock.ExpectCommit()
+
+			if err := app.acceptInvitations(context.Background(), userID, ""); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestAcceptInvitationTokenNeedsInvitedAddress(t *testing.T) {
+	app, _, mock := newTestApplication(t)
+	const userID = 8
+
+	// The token's invitation was sent to an address the user hasn't verified, so
+	// accepting it matches nothing.
+	mock.ExpectBegin()
+	mock.ExpectQuery(`-- name: AcceptLeagueInvitation :one(.|\n)*user_identities\.email_verified`).
+		WithArgs(userID, data.HashToken("token")).
+		WillReturnRows(sqlmock.NewRows(invitationColumns))
+	mock.ExpectQuery("-- name: AcceptLeagueInvitationsByEmail").
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows(invitationColumns))
+	mock.ExpectCommit()
+
+	if err := app.acceptInvitations(context.Background(), userID, "token"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSignInWithMixedCaseEmailAcceptsInvitation(t *testing.T) {
+	app, _, mock := newTestApplication(t)
+	const userID, inviterID = 8, 2
+
+	// The provider reports the invited address in another case; it's recorded
+	// as normalizeEmail leaves it and matched against the invitation without case.
+	mock.ExpectQuery("-- name: RecordIdentityLogin").
+		WithArgs("github", "sub", "bob@example.com", true).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`-- name: AcceptLeagueInvitationsByEmail(.|\n)*lower\(email\) IN \(\s*SELECT lower\(user_identities\.email\)`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows(invitationColumns).AddRow(acceptedInvitation(30, int64(inviterID), userID)...))
+	mock.ExpectQuery("-- name: GetLeagueRole").
+		WithArgs(inviterID, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(data.RoleCommissioner))
+	mock.ExpectQuery("-- name: GetLeagueRole").
+		WithArgs(userID, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}))
+	mock.ExpectQuery("-- name: UpsertLeagueRole").
+		WithArgs(userID, 100, data.RoleMember, inviterID).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "league_id", "role", "granted_by", "created_at", "updated_at"}).
+			AddRow(userID, 100, data.RoleMember, inviterID, time.Now(), time.Now()))
+	mock.ExpectCommit()
+
+	identity := userIdentity{Subject: "sub", Email: "Bob@Example.COM", Name: "Bob", EmailVerified: true}
+	r := httptest.NewRequest(http.MethodGet, "/v1/auth/github/callback", nil)
+	w := httptest.NewRecorder()
+	app.completeLogin(w, r, "github", identity, loginAttempt{})
+
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/v1/dashboard" {
+		t.Fatalf("status %d to %q, want a redirect to the dashboard", w.Code, w.Header().Get("Location"))
+	}
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"database/sql"
+	"fmt"
 	"net/http"
 
+	"github.com/layer8s/home-dashboard-app/internal/cache"
 	"github.com/layer8s/home-dashboard-app/internal/data"
 	"github.com/layer8s/home-dashboard-app/internal/db"
 	"github.com/layer8s/home-dashboard-app/internal/validator"
@@ -17,12 +19,27 @@ func (app *application) showLeagueHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	app.logger.Info("attempting to fetch league", "id", id)
+	format, err := app.negotiateFormat(r)
+	if err != nil {
+		app.notAcceptableResponse(w, r)
+		return
+	}
+
+	rev, err := app.queries.GetArchiveRevision(r.Context())
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if app.revalidateSeason(w, r, rev, format) {
+		return
+	}
+
+	app.requestLogger(r).Info("attempting to fetch league", "id", id)
 
 	// Use the SQLC-generated query method
 	league, err := app.queries.GetLeagueById(r.Context(), int32(id))
 	if err != nil {
-		app.logger.Error("database error", "error", err)
+		app.requestLogger(r).Error("database error", "error", err)
 		if err == sql.ErrNoRows {
 			app.notFoundResponse(w, r)
 			return
@@ -31,7 +48,11 @@ func (app *application) showLeagueHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, envelope{"league": league}, nil)
+	if app.checkNotModified(w, r, rev, format, league.Year) {
+		return
+	}
+
+	err = app.writeFormatted(w, http.StatusOK, format, "league", fmt.Sprintf("league-%d", id), league, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -48,6 +69,12 @@ func (app *application) listLeaguesHandler(w http.ResponseWriter, r *http.Reques
 		data.Filters
 	}
 
+	format, err := app.negotiateFormat(r)
+	if err != nil {
+		app.notAcceptableResponse(w, r)
+		return
+	}
+
 	v := validator.New()
 	qs := r.URL.Query()
 
@@ -85,20 +112,46 @@ func (app *application) listLeaguesHandler(w http.ResponseWriter, r *http.Reques
 		Column9:     sortVal,
 	}
 
-	app.logger.Info("League Params", baseParams)
-	var leagues []db.League
-	var err error
+	app.requestLogger(r).Info("league params", "params", baseParams)
+
+	// Answer conditional requests before running the listing query at all.
+	rev, err := app.queries.GetArchiveRevision(r.Context())
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if app.checkNotModified(w, r, rev, format, input.Year) {
+		return
+	}
 
-	// Check the sort direction and call the appropriate method.
-	if sortDir == "DESC" {
-		descParams := db.GetLeaguesDescParams(baseParams)
-		leagues, err = app.queries.GetLeaguesDesc(r.Context(), descParams)
-	} else {
-		leagues, err = app.queries.GetLeaguesAsc(r.Context(), baseParams)
+	// CSV and NDJSON listings are streamed straight from the database rows.
+	if format != formatJSON {
+		filename := fmt.Sprintf("leagues-page-%d", input.Filters.Page)
+		err = app.streamRows(w, r, format, filename, db.League{}, func(emit func(any) error) error {
+			emitLeague := func(league db.League) error { return emit(league) }
+			if sortDir == "DESC" {
+				return app.queries.EachLeagueDesc(r.Context(), db.GetLeaguesDescParams(baseParams), emitLeague)
+			}
+			return app.queries.EachLeagueAsc(r.Context(), baseParams, emitLeague)
+		})
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+		}
+		return
 	}
 
+	// Cache under the revision the ETag was built from, so the body always matches
+	// it. Check the sort direction and call the appropriate method.
+	key := app.cache.KeyAt(rev.Revision, "leagues", sortDir, baseParams)
+	leagues, err := cache.Fetch(r.Context(), app.cache, key, func() ([]db.League, error) {
+		if sortDir == "DESC" {
+			return app.queries.GetLeaguesDesc(r.Context(), db.GetLeaguesDescParams(baseParams))
+		}
+		return app.queries.GetLeaguesAsc(r.Context(), baseParams)
+	})
+
 	if err != nil {
-		app.logger.Error("database error", "error", err)
+		app.requestLogger(r).Error("database error", "error", err)
 		if err == sql.ErrNoRows {
 			app.notFoundResponse(w, r)
 			return
@@ -130,12 +183,9 @@ func (app *application) leaguesPageHandler(w http.ResponseWriter, r *http.Reques
 		CurrentWeek: -1,
 		NflWeek:     -1,
 	}
-	// Get leagues from database
-	leagues, err := app.queries.GetLeaguesAsc(r.Context(), baseParams)
-	if err != nil {
-		app.logger.Error("database error", "error", err)
-		app.serverErrorResponse(w, r, err)
-		return
+	// Get leagues from database, only when the fragment isn't cached already
+	loadLeagues := func() ([]db.League, error) {
+		return app.queries.GetLeaguesAsc(r.Context(), baseParams)
 	}
 
 	//app.logger.Info("Leagues fetched:", leagues)
@@ -156,17 +206,34 @@ func (app *application) leaguesPageHandler(w http.ResponseWriter, r *http.Reques
 
 	// If it's an HTMX request, return just the table
 	if r.Header.Get("HX-Request") == "true" {
-		err := templates.LeaguesTable(leagues).Render(r.Context(), w)
+		table, err := app.cache.Fragment(r.Context(), app.cache.Key("fragment:leagues-table", baseParams), func() (cache.Renderer, error) {
+			leagues, err := loadLeagues()
+			return templates.LeaguesTable(leagues), err
+		})
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		err = table.Render(r.Context(), w)
 		if err != nil {
 			app.serverErrorResponse(w, r, err)
 		}
 		return
 	}
 
-	// Render the full page inside the base layout
-	err = templates.Base(
-		templates.Leagues(leagues),
-	).Render(r.Context(), w)
+	// Render the full page inside the base layout. Only the leagues content is
+	// cached; the layout around it is cheap to render.
+	content, err := app.cache.Fragment(r.Context(), app.cache.Key("fragment:leagues", baseParams), func() (cache.Renderer, error) {
+		leagues, err := loadLeagues()
+		return templates.Leagues(leagues), err
+	})
+	if err != nil {
+		app.requestLogger(r).Error("database error", "error", err)
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = templates.Base(content).Render(r.Context(), w)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -188,7 +255,7 @@ func (app *application) leaguesRefreshHandler(w http.ResponseWriter, r *http.Req
 
 	leagues, err := app.queries.GetLeaguesAsc(r.Context(), baseParams)
 	if err != nil {
-		app.logger.Error("database error", "error", err)
+		app.requestLogger(r).Error("database error", "error", err)
 		if err == sql.ErrNoRows {
 			app.notFoundResponse(w, r)
 			return
@@ -7,7 +7,7 @@ import (
 )
 
 func (app *application) loginHandler(w http.ResponseWriter, r *http.Request) {
-	loginPage := templates.Login()
+	loginPage := templates.Login(app.authManager.Available(), "", app.config.Session.RememberMeMaxAge > 0)
 	err := loginPage.Render(r.Context(), w)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/oauth2"
+)
+
+// loginAttemptTTL is how long a user has to complete a login at the provider
+// before its state expires.
+const loginAttemptTTL = 10 * time.Minute
+
+var errInvalidLoginState = errors.New("invalid, expired or already used login state")
+
+// loginAttempt is what HandleAuth remembers about a login in progress, for
+// HandleCallback to check the provider's response against. Each attempt is kept
+// in Redis under its own state, so logins started in several tabs don't overwrite
+// each other, and is deleted as it's read, so a state can only be used once.
+type loginAttempt struct {
+	Provider string `json:"provider"`
+	// Verifier is the PKCE code verifier; only its S256 challenge is sent with
+	// the authorization request.
+	Verifier string `json:"verifier"`
+	// Nonce must come back in the ID token, tying the token to this attempt.
+	Nonce string `json:"nonce"`
+	// Binding ties the attempt to the browser session that started it, so a
+	// callback URL can't be replayed from another browser.
+	Binding string `json:"binding"`
+	// LinkUserID is set when a signed-in user is adding this provider to their
+	// account rather than signing in with it.
+	LinkUserID int64 `json:"link_user_id,omitempty"`
+	// Remember asks for a "keep me signed in" session.
+	Remember bool `json:"remember,omitempty"`
+}
+
+func loginAttemptKey(state string) string {
+	return "login_state:" + state
+}
+
+// startLogin records a new login attempt with p and returns its state and the
+// options to add to the authorization URL. linkUserID is the user to link the
+// provider's identity to, or zero for an ordinary login, which remember makes a
+// "keep me signed in" one.
+func (app *application) startLogin(w http.ResponseWriter, r *http.Request, p *AuthProvider, linkUserID int64, remember bool) (string, []oauth2.AuthCodeOption, error) {
+	session, _ := app.sessionStore.Get(r, "auth-session")
+	binding, _ := session.Values["login_binding"].(string)
+	if binding == "" {
+		var err error
+		if binding, err = generateState(); err != nil {
+			return "", nil, err
+		}
+		session.Values["login_binding"] = binding
+		if err := session.Save(r, w); err != nil {
+			return "", nil, err
+		}
+	}
+
+	state, err := generateState()
+	if err != nil {
+		return "", nil, err
+	}
+	nonce, err := generateState()
+	if err != nil {
+		return "", nil, err
+	}
+	attempt := loginAttempt{
+		Provider:   p.Name,
+		Verifier:   oauth2.GenerateVerifier(),
+		Nonce:      nonce,
+		Binding:    binding,
+		LinkUserID: linkUserID,
+		Remember:   remember,
+	}
+
+	data, err := json.Marshal(attempt)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := app.redisClient.Set(r.Context(), loginAttemptKey(state), data, loginAttemptTTL).Err(); err != nil {
+		return "", nil, fmt.Errorf("storing login state: %w", err)
+	}
+
+	opts := []oauth2.AuthCodeOption{oauth2.S256ChallengeOption(attempt.Verifier)}
+	if p.Verifier != nil {
+		opts = append(opts, oidc.Nonce(attempt.Nonce))
+	}
+	return state, opts, nil
+}
+
+// finishLogin consumes the login attempt for state. It returns
+// errInvalidLoginState if there's no such attempt, it has expired, or it was
+// started for another provider or in another browser.
+func (app *application) finishLogin(r *http.Request, provider, state string) (loginAttempt, error) {
+	ctx := r.Context()
+	if state == "" {
+		return loginAttempt{}, errInvalidLoginState
+	}
+
+	// GET and DEL in one transaction, so two callbacks racing with the same state
+	// can't both succeed.
+	var get *redis.StringCmd
+	_, err := app.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, loginAttemptKey(state))
+		pipe.Del(ctx, loginAttemptKey(state))
+		return nil
+	})
+	if errors.Is(err, redis.Nil) {
+		return loginAttempt{}, errInvalidLoginState
+	}
+	if err != nil {
+		return loginAttempt{}, fmt.Errorf("reading login state: %w", err)
+	}
+
+	var attempt loginAttempt
+	if err := json.Unmarshal([]byte(get.Val()), &attempt); err != nil {
+		return loginAttempt{}, fmt.Errorf("reading login state: %w", err)
+	}
+
+	session, _ := app.sessionStore.Get(r, "auth-session")
+	binding, _ := session.Values["login_binding"].(string)
+	if attempt.Provider != provider || binding == "" || attempt.Binding != binding {
+		return loginAttempt{}, errInvalidLoginState
+	}
+
+	return attempt, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoginStateSingleUse(t *testing.T) {
+	app, _, _ := newTestApplication(t)
+	provider := &AuthProvider{Name: "github"}
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/auth/github", nil)
+	w := httptest.NewRecorder()
+	state, opts, err := app.startLogin(w, r, provider, 7, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opts) != 1 {
+		t.Errorf("got %d authorization options, want only the PKCE challenge", len(opts))
+	}
+
+	callback := func() *http.Request {
+		return withCookies(httptest.NewRequest(http.MethodGet, "/v1/auth/github/callback", nil), w)
+	}
+
+	attempt, err := app.finishLogin(callback(), "github", state)
+	if err != nil {
+		t.Fatalf("finishLogin: %v", err)
+	}
+	if attempt.Verifier == "" || attempt.Nonce == "" || attempt.LinkUserID != 7 || !attempt.Remember {
+		t.Errorf("unexpected attempt %+v", attempt)
+	}
+
+	if _, err := app.finishLogin(callback(), "github", state); !errors.Is(err, errInvalidLoginState) {
+		t.Errorf("reusing the state: got %v, want errInvalidLoginState", err)
+	}
+}
+
+func TestLoginStateRejected(t *testing.T) {
+	app, _, _ := newTestApplication(t)
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/auth/github", nil)
+	w := httptest.NewRecorder()
+	state, _, err := app.startLogin(w, r, &AuthProvider{Name: "github"}, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromBrowser := withCookies(httptest.NewRequest(http.MethodGet, "/v1/auth/github/callback", nil), w)
+
+	tests := []struct {
+		name     string
+		r        *http.Request
+		provider string
+		state    string
+	}{
+		{"empty state", fromBrowser, "github", ""},
+		{"unknown state", fromBrowser, "github", "made-up"},
+		{"another browser", httptest.NewRequest(http.MethodGet, "/v1/auth/github/callback", nil), "github", state},
+	}
+	for _, tt := range tests {
+		if _, err := app.finishLogin(tt.r, tt.provider, tt.state); !errors.Is(err, errInvalidLoginState) {
+			t.Errorf("%s: got %v, want errInvalidLoginState", tt.name, err)
+		}
+	}
+
+	// Looking the state up from another browser used it up, so start another
+	// attempt from the same browser for another provider's callback.
+	again := withCookies(httptest.NewRequest(http.MethodGet, "/v1/auth/github", nil), w)
+	state, _, err = app.startLogin(httptest.NewRecorder(), again, &AuthProvider{Name: "github"}, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.finishLogin(fromBrowser, "google", state); !errors.Is(err, errInvalidLoginState) {
+		t.Errorf("another provider: got %v, want errInvalidLoginState", err)
+	}
+}
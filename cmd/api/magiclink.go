@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/layer8s/home-dashboard-app/internal/config"
+	"github.com/layer8s/home-dashboard-app/internal/data"
+	"github.com/layer8s/home-dashboard-app/internal/validator"
+	"github.com/layer8s/home-dashboard-app/templates"
+)
+
+// magicLinkTTL is how long a sign-in link can be used for.
+const magicLinkTTL = 15 * time.Minute
+
+var errInvalidMagicLink = errors.New("invalid, expired or already used sign-in link")
+
+// magicLinkKey is where the email address a sign-in link was sent to is kept in
+// Redis. Only a hash of the token is used, so the keys can't be used to sign in.
+func magicLinkKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "magic_link:" + hex.EncodeToString(sum[:])
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// requestMagicLinkHandler emails a single-use sign-in link to the address given.
+// Anyone can ask for one, registered or not: following the link signs in the
+// user who has the address, or creates one. Requests are limited per client, by
+// the route, and per address, here.
+func (app *application) requestMagicLinkHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Email string `json:"email"`
+	}
+
+	jsonRequest := isJSONRequest(r)
+	if jsonRequest {
+		if err := app.readJSON(w, r, &input); err != nil {
+			app.badRequestResponse(w, r, err)
+			return
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			app.badRequestResponse(w, r, err)
+			return
+		}
+		input.Email = r.PostForm.Get("email")
+	}
+
+	email := normalizeEmail(input.Email)
+	v := validator.New()
+	if data.ValidateEmail(v, email); !v.Valid() {
+		if jsonRequest {
+			app.failedValidationResponse(w, r, v.Errors)
+		} else {
+			app.renderLoginError(w, r, http.StatusUnprocessableEntity, "Enter a valid email address to get a sign-in link.")
+		}
+		return
+	}
+
+	sum := sha256.Sum256([]byte(email))
+	if !app.allowRequest(w, r, "magic_link_email", "email:"+hex.EncodeToString(sum[:16])) {
+		if jsonRequest {
+			app.rateLimitExceededResponse(w, r)
+		} else {
+			app.renderLoginError(w, r, http.StatusTooManyRequests, "Too many sign-in links have been sent to that address. Please try again later.")
+		}
+		return
+	}
+
+	token, err := generateState()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if err := app.redisClient.Set(r.Context(), magicLinkKey(token), email, magicLinkTTL).Err(); err != nil {
+		app.serverErrorResponse(w, r, fmt.Errorf("storing sign-in link: %w", err))
+		return
+	}
+
+	link := app.config.Auth.BaseCallbackURL + "/v1/login/magic?token=" + url.QueryEscape(token)
+	app.background(r.Context(), func(ctx context.Context) {
+		data := map[string]any{
+			"link":             link,
+			"expiresInMinutes": int(magicLinkTTL.Minutes()),
+		}
+
+		err := app.mailer.Send(ctx, email, "magic_link.tmpl", data)
+		if err != nil {
+			app.loggerFromContext(ctx).Error("sending sign-in link failed", "error", err)
+		}
+	})
+
+	if !jsonRequest {
+		err = templates.Base(templates.MagicLinkSent(email)).Render(r.Context(), w)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+	env := envelope{"message": "a sign-in link has been sent to that email address"}
+	err = app.writeJSON(w, http.StatusAccepted, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// magicLinkHandler is where the emailed link leads. It only asks the person to
+// confirm, so mail scanners that follow links don't use up the token.
+func (app *application) magicLinkHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	err := templates.Base(templates.MagicLinkConfirm(token, app.config.Session.RememberMeMaxAge > 0)).Render(r.Context(), w)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// magicLinkVerifyHandler uses up the sign-in link's token and signs in the
+// owner of the email address it was sent to, exactly as if an identity provider
+// had vouched for the address.
+func (app *application) magicLinkVerifyHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	email, err := app.consumeMagicLink(r.Context(), r.PostForm.Get("token"))
+	if errors.Is(err, errInvalidMagicLink) {
+		app.renderLoginError(w, r, http.StatusBadRequest, "That sign-in link is invalid, has expired or has already been used. Please ask for a new one.")
+		return
+	}
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	localPart, _, _ := strings.Cut(email, "@")
+	identity := userIdentity{
+		Subject:       email,
+		Email:         email,
+		Name:          localPart,
+		EmailVerified: true,
+	}
+	app.completeLogin(w, r, config.MagicLinkProvider, identity, loginAttempt{Remember: r.PostForm.Get("remember") != ""})
+}
+
+// consumeMagicLink returns the email address the link with token was sent to and
+// deletes it, so each link only works once.
+func (app *application) consumeMagicLink(ctx context.Context, token string) (string, error) {
+	if token == "" {
+		return "", errInvalidMagicLink
+	}
+
+	var get *redis.StringCmd
+	_, err := app.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, magicLinkKey(token))
+		pipe.Del(ctx, magicLinkKey(token))
+		return nil
+	})
+	if errors.Is(err, redis.Nil) {
+		return "", errInvalidMagicLink
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading sign-in link: %w", err)
+	}
+	return get.Val(), nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/layer8s/home-dashboard-app/internal/config"
+)
+
+func TestConsumeMagicLinkSingleUse(t *testing.T) {
+	app, mr, _ := newTestApplication(t)
+	ctx := context.Background()
+
+	if err := mr.Set(magicLinkKey("token"), "alice@example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	email, err := app.consumeMagicLink(ctx, "token")
+	if err != nil || email != "alice@example.com" {
+		t.Fatalf("first use: got %q, %v", email, err)
+	}
+	if _, err := app.consumeMagicLink(ctx, "token"); !errors.Is(err, errInvalidMagicLink) {
+		t.Errorf("second use: got %v, want errInvalidMagicLink", err)
+	}
+	for _, token := range []string{"", "unknown"} {
+		if _, err := app.consumeMagicLink(ctx, token); !errors.Is(err, errInvalidMagicLink) {
+			t.Errorf("token %q: got %v, want errInvalidMagicLink", token, err)
+		}
+	}
+}
+
+func TestConsumeMagicLinkExpired(t *testing.T) {
+	app, mr, _ := newTestApplication(t)
+
+	if err := mr.Set(magicLinkKey("token"), "alice@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	mr.SetTTL(magicLinkKey("token"), magicLinkTTL)
+	mr.FastForward(magicLinkTTL)
+
+	if _, err := app.consumeMagicLink(context.Background(), "token"); !errors.Is(err, errInvalidMagicLink) {
+		t.Errorf("got %v, want errInvalidMagicLink", err)
+	}
+}
+
+func TestMagicLinkVerifySignsIn(t *testing.T) {
+	app, mr, mock := newTestApplication(t)
+	const userID = 12
+
+	if err := mr.Set(magicLinkKey("token"), "alice@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectQuery("-- name: RecordIdentityLogin").
+		WithArgs(config.MagicLinkProvider, "alice@example.com", sqlmock.AnyArg(), true).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID))
+	mock.ExpectBegin()
+	mock.ExpectQuery("-- name: AcceptLeagueInvitationsByEmail").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	form := url.Values{"token": {"token"}}
+	r := httptest.NewRequest(http.MethodPost, "/v1/login/magic/verify", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	app.magicLinkVerifyHandler(w, r)
+
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/v1/dashboard" {
+		t.Fatalf("status %d to %q, want a redirect to the dashboard", w.Code, w.Header().Get("Location"))
+	}
+	if mr.Exists(magicLinkKey("token")) {
+		t.Error("the sign-in link can be used again")
+	}
+	ids, err := mr.Members(userSessionsKey(userID))
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("tracked sessions: %v, %v", ids, err)
+	}
+}
+
+func TestRequestMagicLinkEmailLimit(t *testing.T) {
+	app, mr, _ := newTestApplication(t)
+	app.config.Limiter.Groups["magic_link_email"] = config.RateLimit{Requests: 1, Window: time.Minute}
+
+	// Use up the address's limit, which is kept by a hash of the address.
+	sum := sha256.Sum256([]byte("alice@example.com"))
+	r := httptest.NewRequest(http.MethodPost, "/v1/login/magic", nil)
+	if !app.allowRequest(httptest.NewRecorder(), r, "magic_link_email", "email:"+hex.EncodeToString(sum[:16])) {
+		t.Fatal("the first request was limited")
+	}
+
+	// The same address, however it's written, gets no more links.
+	r = httptest.NewRequest(http.MethodPost, "/v1/login/magic", strings.NewReader(`{"email": " Alice@Example.com "}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	app.requestMagicLinkHandler(w, r)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("status %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	for _, key := range mr.Keys() {
+		if strings.HasPrefix(key, "magic_link:") {
+			t.Errorf("a sign-in link was stored past the limit: %s", key)
+		}
+	}
+}
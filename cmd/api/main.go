@@ -3,16 +3,24 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/gorilla/sessions"
+	"github.com/graphql-go/graphql"
+	"github.com/layer8s/home-dashboard-app/internal/cache"
+	"github.com/layer8s/home-dashboard-app/internal/config"
+	"github.com/layer8s/home-dashboard-app/internal/data"
 	"github.com/layer8s/home-dashboard-app/internal/db"
+	"github.com/layer8s/home-dashboard-app/internal/graph"
 	"github.com/layer8s/home-dashboard-app/internal/mailer"
 	_ "github.com/lib/pq"
 	"github.com/rbcervilla/redisstore/v8"
@@ -20,96 +28,47 @@ import (
 
 const version = "1.0.0"
 
-type AuthConfig struct {
-	BaseCallbackURL string
-	Providers       map[string]ProviderConfig
-}
-
-// ProviderConfig holds the configuration for a specific auth provider
-type ProviderConfig struct {
-	Name            string
-	ClientID        string
-	ClientSecret    string
-	Scopes          []string
-	BaseCallbackURL string
-	ExtraConfig     map[string]string
-}
-
-type config struct {
-	port int
-	env  string
-	db   struct {
-		dsn          string
-		maxOpenConns int
-		maxIdleConns int
-		maxIdleTime  time.Duration
-	}
-	redis struct {
-		addr     string
-		password string
-		db       int
-	}
-	auth struct {
-		baseCallbackURL string
-		providers       map[string]ProviderConfig
-	}
-	sessionKey string
-}
-
 type application struct {
-	config       config
-	logger       *slog.Logger
-	queries      *db.Queries
-	sessionStore sessions.Store
-	mailer       *mailer.Mailer
-	wg           sync.WaitGroup
-	authManager  *AuthManager
-	redisClient  *redis.Client
+	config        *config.Config
+	logger        *slog.Logger
+	db            *sql.DB
+	queries       *db.Queries
+	tokens        *data.TokenModel
+	sessionStore  sessions.Store
+	mailer        *mailer.Mailer
+	wg            sync.WaitGroup
+	authManager   *AuthManager
+	redisClient   *redis.Client
+	graphqlSchema graphql.Schema
+	cache         *cache.Cache
+	metrics       *metrics
 }
 
 func main() {
-	// Load environment variables
-	port, env, dsn, dbMaxOpenConns, dbMaxIdleConns, dbMaxIdleTime, sessionKey, sendGridKey, redisAddr, redisPassword, redisDB := loadEnvironment()
-
-	var cfg config
-
-	//Command line flags, default values set via .env variables
-	flag.IntVar(&cfg.port, "port", port, "API Server Port")
-	flag.StringVar(&cfg.env, "env", env, "Environment (development|staging|production)")
-	flag.StringVar(&cfg.db.dsn, "db-dsn", dsn, "PostgreSQL DSN")
-	flag.IntVar(&cfg.db.maxOpenConns, "db-max-open-conns", dbMaxOpenConns, "PostgreSQL max open connections")
-	flag.IntVar(&cfg.db.maxIdleConns, "db-max-idle-conns", dbMaxIdleConns, "PostgreSQL max idle connections")
-	flag.DurationVar(&cfg.db.maxIdleTime, "db-max-idle-time", dbMaxIdleTime, "PostgreSQL max connection idle time")
-	flag.Parse()
-
-	cfg.redis.addr = redisAddr
-	cfg.redis.password = redisPassword
-	cfg.redis.db = redisDB
-	cfg.auth.baseCallbackURL = fmt.Sprintf("http://localhost:%d", port)
-	cfg.auth.providers = map[string]ProviderConfig{
-		"auth0": {
-			Name:            "auth0",
-			ClientID:        os.Getenv("AUTH0_CLIENT_ID"),
-			ClientSecret:    os.Getenv("AUTH0_CLIENT_SECRET"),
-			Scopes:          []string{"openid", "profile", "email"},
-			BaseCallbackURL: cfg.auth.baseCallbackURL,
-			ExtraConfig: map[string]string{
-				"issuer": fmt.Sprintf("https://%s/", os.Getenv("AUTH0_DOMAIN")),
-			},
-		},
-		"google": {
-			Name:            "google",
-			ClientID:        os.Getenv("GOOGLE_CLIENT_ID"),
-			ClientSecret:    os.Getenv("GOOGLE_CLIENT_SECRET"),
-			Scopes:          []string{"openid", "profile", "email"},
-			BaseCallbackURL: cfg.auth.baseCallbackURL,
-			ExtraConfig: map[string]string{
-				"issuer": "https://accounts.google.com",
-			},
-		},
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		os.Exit(2)
 	}
 
-	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	var logHandler slog.Handler
+	if cfg.Log.Format == "json" {
+		logHandler = slog.NewJSONHandler(os.Stdout, nil)
+	} else {
+		logHandler = slog.NewTextHandler(os.Stdout, nil)
+	}
+	logger := slog.New(logHandler)
+	logger.Info("configuration loaded", "env", cfg.Env, "sources", strings.Join(cfg.Sources, ","))
+
+	shutdownTracing, err := setupTracing(cfg)
+	if err != nil {
+		logger.Error("failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+	defer shutdownTracing(context.Background())
 
 	redisClient, err := openRedis(cfg)
 	if err != nil {
@@ -127,12 +86,15 @@ func main() {
 	}
 
 	// Configure session options
-	store.KeyPrefix("session_")
+	store.KeyPrefix(sessionKeyPrefix)
 	store.Options(sessions.Options{
 		Path:     "/",
-		MaxAge:   3600 * 24, // 24 hours
+		MaxAge:   int(cfg.Session.MaxAge.Seconds()),
 		HttpOnly: true,
-		Secure:   cfg.env == "production", // Only secure in production
+		Secure:   cfg.Env == "production", // Only secure in production
+		// Lax keeps the cookie off cross-site POSTs and subrequests while still
+		// sending it on top-level links, such as the ones in sign-in emails.
+		SameSite: http.SameSiteLaxMode,
 	})
 
 	dbConn, err := openDB(cfg)
@@ -145,48 +107,75 @@ func main() {
 
 	logger.Info("database connection pool established")
 
-	queries := db.New(dbConn)
+	queries := db.New(db.Traced(dbConn))
+
+	graphqlSchema, err := graph.NewSchema(queries)
+	if err != nil {
+		logger.Error("failed to build GraphQL schema", "error", err)
+		os.Exit(1)
+	}
+
+	// Cached entries are keyed by archive revision; start from the current one and
+	// follow the notifications sent as imports commit.
+	rev, err := queries.GetArchiveRevision(context.Background())
+	if err != nil {
+		logger.Error("failed to read archive revision", "error", err)
+		os.Exit(1)
+	}
+	archiveCache := cache.New(redisClient, logger, cfg.Cache.TTL)
+	archiveCache.SetVersion(rev.Revision)
 
-	cfg.sessionKey = sessionKey
+	// Long-running workers stop once the server has.
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go archiveCache.Listen(ctx, cfg.DB.DSN, func(ctx context.Context) (int64, error) {
+		rev, err := queries.GetArchiveRevision(ctx)
+		return rev.Revision, err
+	})
+	go archiveCache.ReportStats(ctx, cfg.Cache.StatsInterval)
 
 	app := &application{
-		config:       cfg,
-		logger:       logger,
-		queries:      queries,
-		sessionStore: store,
-		mailer:       mailer.New(sendGridKey, "FFArchive <robert@litts.org>", logger),
-		authManager:  NewAuthManager(),
-		redisClient:  redisClient,
+		config:        cfg,
+		logger:        logger,
+		db:            dbConn,
+		queries:       queries,
+		tokens:        data.NewTokenService(queries),
+		sessionStore:  store,
+		mailer:        mailer.New(cfg.Mail.SendGridKey, cfg.Mail.Sender, logger),
+		authManager:   NewAuthManager(cfg.Auth.BaseCallbackURL, logger),
+		redisClient:   redisClient,
+		graphqlSchema: graphqlSchema,
+		cache:         archiveCache,
+		metrics:       newMetrics(dbConn, redisClient, queries),
 	}
 
-	// Initialize the auth providers
-	ctx := context.Background()
-	for _, providerConfig := range cfg.auth.providers {
-		if err := app.authManager.RegisterProvider(ctx, providerConfig); err != nil {
-			logger.Error("failed to register provider", "error", err, "provider", providerConfig.Name)
-			os.Exit(1)
-		}
+	// Providers are set up in the background, and on demand, so one that's down
+	// only disables signing in with it rather than stopping the server.
+	for _, providerConfig := range cfg.Auth.Providers {
+		app.authManager.AddProvider(providerConfig)
 	}
+	go app.authManager.Run(ctx)
 
 	// Call app.serve() to start the server.
 	err = app.serve()
+	cancel()
 	if err != nil {
 		logger.Error(err.Error())
 		os.Exit(1)
 	}
 }
 
-func openDB(cfg config) (*sql.DB, error) {
+func openDB(cfg *config.Config) (*sql.DB, error) {
 	// Use sql.Open() to create an empty connection pool, using the DSN from the config
 	// struct.
-	db, err := sql.Open("postgres", cfg.db.dsn)
+	db, err := sql.Open("postgres", cfg.DB.DSN)
 	if err != nil {
 		return nil, err
 	}
 
-	db.SetMaxOpenConns(cfg.db.maxOpenConns)
-	db.SetMaxIdleConns(cfg.db.maxIdleConns)
-	db.SetConnMaxIdleTime(cfg.db.maxIdleTime)
+	db.SetMaxOpenConns(cfg.DB.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.DB.MaxIdleConns)
+	db.SetConnMaxIdleTime(cfg.DB.MaxIdleTime)
 
 	// Create a context with a 5-second timeout deadline.
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -207,13 +196,15 @@ func openDB(cfg config) (*sql.DB, error) {
 	return db, nil
 }
 
-func openRedis(cfg config) (*redis.Client, error) {
+func openRedis(cfg *config.Config) (*redis.Client, error) {
 	rdb := redis.NewClient(&redis.Options{
-		Addr:     cfg.redis.addr,
-		Password: cfg.redis.password,
-		DB:       cfg.redis.db,
+		Addr:     cfg.Redis.Addr,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
 	})
 
+	rdb.AddHook(redisTracingHook{})
+
 	// Test the connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
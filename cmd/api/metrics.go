@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/julienschmidt/httprouter"
+	"github.com/layer8s/home-dashboard-app/internal/db"
+	"github.com/layer8s/home-dashboard-app/internal/mailer"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metrics holds the application's Prometheus collectors. They're registered with a
+// dedicated registry rather than the global one so /metrics only exposes what is
+// listed in newMetrics.
+type metrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	inFlight        prometheus.Gauge
+	backgroundTasks prometheus.Gauge
+}
+
+func newMetrics(dbConn *sql.DB, redisClient *redis.Client, queries *db.Queries) *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests served, by method, route pattern and status code.",
+		}, []string{"method", "route", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Time taken to serve HTTP requests, by method and route pattern.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "HTTP requests currently being served.",
+		}),
+		backgroundTasks: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "background_tasks",
+			Help: "Goroutines started with app.background that haven't finished yet.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(dbConn, "archive"),
+		m.requests,
+		m.requestDuration,
+		m.inFlight,
+		m.backgroundTasks,
+		mailer.SendsTotal,
+		redisPoolCollector{client: redisClient},
+		archiveCollector{queries: queries},
+	)
+
+	return m
+}
+
+func (m *metrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// instrument records the request count, status and latency of next under route,
+// which is the pattern the handler was registered with so that label values stay
+// bounded however many leagues and teams there are.
+func (m *metrics) instrument(route string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		m.inFlight.Inc()
+		defer m.inFlight.Dec()
+
+		rec := newResponseRecorder(w)
+		next.ServeHTTP(rec, r)
+
+		m.requests.WithLabelValues(r.Method, route, strconv.Itoa(rec.status)).Inc()
+		m.requestDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// instrumentedRouter wraps every handler registered through it with metrics
+// labelled by its route pattern, which httprouter doesn't otherwise expose, and
+// names the request's trace span after it. It records the routes registered, as "METHOD pattern", for
+// checkAPIRoutes.
+type instrumentedRouter struct {
+	*httprouter.Router
+	metrics *metrics
+	routes  []string
+}
+
+func (app *application) instrumentRouter(router *httprouter.Router) *instrumentedRouter {
+	return &instrumentedRouter{Router: router, metrics: app.metrics}
+}
+
+func (ir *instrumentedRouter) Handler(method, path string, handler http.Handler) {
+	ir.routes = append(ir.routes, method+" "+path)
+	ir.Router.Handler(method, path, ir.metrics.instrument(path, traceRoute(method, path, handler)))
+}
+
+func (ir *instrumentedRouter) HandlerFunc(method, path string, handler http.HandlerFunc) {
+	ir.Handler(method, path, handler)
+}
+
+var (
+	redisPoolHitsDesc       = prometheus.NewDesc("redis_pool_hits_total", "Times a free connection was found in the Redis pool.", nil, nil)
+	redisPoolMissesDesc     = prometheus.NewDesc("redis_pool_misses_total", "Times a free connection was not found in the Redis pool.", nil, nil)
+	redisPoolTimeoutsDesc   = prometheus.NewDesc("redis_pool_timeouts_total", "Times a wait for a Redis connection timed out.", nil, nil)
+	redisPoolTotalConnsDesc = prometheus.NewDesc("redis_pool_connections", "Connections in the Redis pool.", nil, nil)
+	redisPoolIdleConnsDesc  = prometheus.NewDesc("redis_pool_idle_connections", "Idle connections in the Redis pool.", nil, nil)
+	redisPoolStaleConnsDesc = prometheus.NewDesc("redis_pool_stale_connections_total", "Stale connections removed from the Redis pool.", nil, nil)
+)
+
+// redisPoolCollector exports the go-redis connection pool statistics.
+type redisPoolCollector struct {
+	client *redis.Client
+}
+
+func (c redisPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- redisPoolHitsDesc
+	ch <- redisPoolMissesDesc
+	ch <- redisPoolTimeoutsDesc
+	ch <- redisPoolTotalConnsDesc
+	ch <- redisPoolIdleConnsDesc
+	ch <- redisPoolStaleConnsDesc
+}
+
+func (c redisPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+	ch <- prometheus.MustNewConstMetric(redisPoolHitsDesc, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(redisPoolMissesDesc, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(redisPoolTimeoutsDesc, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(redisPoolTotalConnsDesc, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(redisPoolIdleConnsDesc, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(redisPoolStaleConnsDesc, prometheus.CounterValue, float64(stats.StaleConns))
+}
+
+var (
+	archiveRevisionDesc       = prometheus.NewDesc("archive_revision", "Current archive revision, bumped by every import.", nil, nil)
+	archiveLastImportDesc     = prometheus.NewDesc("archive_last_import_timestamp_seconds", "Start time of the most recent import.", nil, nil)
+	archiveImportDurationDesc = prometheus.NewDesc("archive_last_import_duration_seconds", "Duration of the most recent import transaction.", nil, nil)
+)
+
+// archiveCollector reports the state of the most recent import, as recorded by the
+// archive_revision trigger. Imports run outside this process, so these are read at
+// scrape time rather than observed as they happen.
+type archiveCollector struct {
+	queries *db.Queries
+}
+
+func (c archiveCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- archiveRevisionDesc
+	ch <- archiveLastImportDesc
+	ch <- archiveImportDurationDesc
+}
+
+func (c archiveCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rev, err := c.queries.GetArchiveRevision(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(archiveRevisionDesc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(archiveRevisionDesc, prometheus.GaugeValue, float64(rev.Revision))
+	ch <- prometheus.MustNewConstMetric(archiveLastImportDesc, prometheus.GaugeValue, float64(rev.UpdatedAt.Unix()))
+	ch <- prometheus.MustNewConstMetric(archiveImportDurationDesc, prometheus.GaugeValue, rev.LastImportSeconds)
+}
@@ -1,8 +1,17 @@
 package main
 
 import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
+
+	"github.com/layer8s/home-dashboard-app/internal/data"
+	"github.com/layer8s/home-dashboard-app/internal/validator"
 )
 
 func (app *application) recoverPanic(next http.Handler) http.Handler {
@@ -34,3 +43,225 @@ func (app *application) requireAuthenticated(next http.HandlerFunc) http.Handler
 		next(w, r)
 	}
 }
+
+// authenticate checks the personal API token of requests with an
+// "Authorization: Bearer <token>" header and adds the user it belongs to to the
+// request context. Requests without the header, or with another scheme such as
+// Basic from a proxy in front of the server, pass through untouched. A token
+// that's malformed, unknown, revoked or expired is rejected rather than ignored,
+// so a script with a bad token fails instead of quietly carrying on anonymously.
+func (app *application) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, plaintext, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		plaintext = strings.TrimSpace(plaintext)
+		v := validator.New()
+		if data.ValidateTokenPlaintext(v, plaintext); !v.Valid() {
+			app.invalidAuthenticationTokenResponse(w, r)
+			return
+		}
+
+		tokenUser, err := app.tokens.GetUserForAPIToken(r.Context(), plaintext)
+		switch {
+		case errors.Is(err, data.ErrTokenNotFound):
+			app.invalidAuthenticationTokenResponse(w, r)
+			return
+		case err != nil:
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		if err := app.tokens.Touch(r.Context(), tokenUser.TokenID); err != nil {
+			app.logError(r, err)
+		}
+
+		next.ServeHTTP(w, app.contextSetUser(r, &authenticatedUser{
+			ID:      tokenUser.UserID,
+			Name:    tokenUser.Name,
+			TokenID: tokenUser.TokenID,
+			Scope:   tokenUser.Scope,
+		}))
+	})
+}
+
+// requireUser guards JSON endpoints that act on behalf of a user. It accepts
+// requests authenticated by an API token or a signed-in browser session, adding
+// the session's user to the context, and answers anything else with 401 rather
+// than the redirect requireAuthenticated sends browsers. Read-only tokens are
+// refused for anything but GET and HEAD, and so are sessions: browsers send the
+// session cookie with requests other sites make too, so only a token can change
+// anything.
+func (app *application) requireUser(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if user := contextGetUser(r); user != nil {
+			if user.Scope == data.ScopeAPIReadOnly && r.Method != http.MethodGet && r.Method != http.MethodHead {
+				app.readOnlyTokenResponse(w, r)
+				return
+			}
+			next(w, r)
+			return
+		}
+
+		session, _ := app.sessionStore.Get(r, "auth-session")
+		if authenticated, _ := session.Values["authenticated"].(bool); authenticated {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				app.sessionReadOnlyResponse(w, r)
+				return
+			}
+			user, err := app.sessionUser(r)
+			switch {
+			case err == nil:
+				next(w, app.contextSetUser(r, &authenticatedUser{ID: user.ID, Name: user.Name}))
+				return
+			case !errors.Is(err, sql.ErrNoRows):
+				app.serverErrorResponse(w, r, err)
+				return
+			}
+		}
+
+		app.authenticationRequiredResponse(w, r)
+	}
+}
+
+// requireLeagueRole sits beside requireAuthenticated and requireUser for routes
+// under /v1/leagues/:id. It authenticates the request as requireUser does, then
+// lets it through only if the user's role in the league is at least min. The
+// league and role are left in the request context for the handler.
+func (app *application) requireLeagueRole(min string, next http.HandlerFunc) http.HandlerFunc {
+	return app.requireUser(func(w http.ResponseWriter, r *http.Request) {
+		id, err := app.readIDParam(r)
+		if err != nil {
+			app.notFoundResponse(w, r)
+			return
+		}
+
+		league, err := app.queries.GetLeagueById(r.Context(), int32(id))
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			app.notFoundResponse(w, r)
+			return
+		case err != nil:
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		user := contextGetUser(r)
+		role, err := app.leagueRole(r.Context(), user.ID, league.LeagueId)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		if !data.RoleAtLeast(role, min) {
+			app.notPermittedResponse(w, r)
+			return
+		}
+
+		next(w, app.contextSetLeagueAccess(r, &leagueAccess{League: league, Role: role}))
+	})
+}
+
+// requestID tags each request with an ID, taken from the X-Request-ID header when the
+// client or a proxy in front of us sent a usable one and generated otherwise. The
+// ID is echoed in the response and carried by the request-scoped logger, so every
+// line logged while serving the request can be tied together.
+func (app *application) requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set("X-Request-ID", id)
+		next.ServeHTTP(w, app.contextSetRequestID(r, id))
+	})
+}
+
+// validRequestID accepts IDs of up to 128 characters made of letters, digits and
+// the punctuation commonly used by proxies and UUIDs, so untrusted input can't
+// forge extra fields in the logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// logRequest writes an access log line for every request once it has been served.
+func (app *application) logRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := newResponseRecorder(w)
+
+		next.ServeHTTP(rec, r)
+
+		app.requestLogger(r).Info("request completed",
+			"method", r.Method,
+			"uri", r.URL.RequestURI(),
+			"proto", r.Proto,
+			"status", rec.status,
+			"bytes", rec.bytes,
+			"duration", time.Since(start),
+			"remote_addr", r.RemoteAddr,
+			"user_agent", r.UserAgent(),
+		)
+	})
+}
+
+// responseRecorder captures the status code and size of a response for metrics and
+// access logs. It passes Flush through for the streaming CSV and NDJSON responses
+// and the Connect handlers.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
+	return &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+func (rec *responseRecorder) Flush() {
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (rec *responseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/sessions"
+)
+
+func TestAuthenticateSchemes(t *testing.T) {
+	app := &application{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	tests := []struct {
+		header string
+		want   int
+	}{
+		{"", http.StatusNoContent},
+		{"Basic dXNlcjpwYXNz", http.StatusNoContent},
+		{"Digest username=\"user\"", http.StatusNoContent},
+		{"Bearer not-a-token", http.StatusUnauthorized},
+		{"bearer not-a-token", http.StatusUnauthorized},
+		{"Bearer", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/v1/tokens", nil)
+		if tt.header != "" {
+			r.Header.Set("Authorization", tt.header)
+		}
+		w := httptest.NewRecorder()
+		app.authenticate(next).ServeHTTP(w, r)
+		if w.Code != tt.want {
+			t.Errorf("Authorization %q: status %d, want %d", tt.header, w.Code, tt.want)
+		}
+	}
+}
+
+func TestRequireUserSessionReadOnly(t *testing.T) {
+	store := sessions.NewCookieStore([]byte("0123456789abcdef0123456789abcdef"))
+	app := &application{sessionStore: store, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	session, _ := store.Get(r, "auth-session")
+	session.Values["authenticated"] = true
+	if err := session.Save(r, w); err != nil {
+		t.Fatal(err)
+	}
+	cookie := w.Result().Cookies()[0]
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodDelete} {
+		r := httptest.NewRequest(method, "/v1/tokens", nil)
+		r.AddCookie(cookie)
+		w := httptest.NewRecorder()
+		app.requireUser(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("%s: handler called with a session", method)
+		})(w, r)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status %d, want %d", method, w.Code, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestReadJSONContentType(t *testing.T) {
+	app := &application{}
+	tests := []struct {
+		contentType string
+		ok          bool
+	}{
+		{"application/json", true},
+		{"application/json; charset=utf-8", true},
+		{"", false},
+		{"text/plain", false},
+		{"application/x-www-form-urlencoded", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"x"}`))
+		if tt.contentType != "" {
+			r.Header.Set("Content-Type", tt.contentType)
+		}
+		var input struct {
+			Name string `json:"name"`
+		}
+		err := app.readJSON(httptest.NewRecorder(), r, &input)
+		if (err == nil) != tt.ok {
+			t.Errorf("Content-Type %q: error %v, want ok=%v", tt.contentType, err, tt.ok)
+		}
+	}
+}
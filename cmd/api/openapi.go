@@ -0,0 +1,1018 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/layer8s/home-dashboard-app/internal/data"
+	"github.com/layer8s/home-dashboard-app/internal/db"
+	"github.com/layer8s/home-dashboard-app/internal/validator"
+	"github.com/layer8s/home-dashboard-app/templates"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// apiSchema is the subset of the OpenAPI 3 schema object used to describe the API.
+// The same values drive both the published document and request validation.
+type apiSchema struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Type        string               `json:"type,omitempty"`
+	Format      string               `json:"format,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Enum        []string             `json:"enum,omitempty"`
+	Minimum     *float64             `json:"minimum,omitempty"`
+	Maximum     *float64             `json:"maximum,omitempty"`
+	MinLength   *int                 `json:"minLength,omitempty"`
+	MaxLength   *int                 `json:"maxLength,omitempty"`
+	Items       *apiSchema           `json:"items,omitempty"`
+	Properties  map[string]apiSchema `json:"properties,omitempty"`
+	Required    []string             `json:"required,omitempty"`
+	Nullable    bool                 `json:"nullable,omitempty"`
+
+	AdditionalProperties *apiSchema `json:"additionalProperties,omitempty"`
+}
+
+type apiParam struct {
+	Name        string    `json:"name"`
+	In          string    `json:"in"`
+	Description string    `json:"description,omitempty"`
+	Required    bool      `json:"required,omitempty"`
+	Schema      apiSchema `json:"schema"`
+}
+
+// apiOperation describes one route. Path uses httprouter syntax (":id"); it is
+// converted to OpenAPI templating ("{id}") when the document is built.
+type apiOperation struct {
+	Method       string
+	Path         string
+	Summary      string
+	Tag          string
+	Params       []apiParam
+	Body         *apiSchema
+	Responses    map[int]apiResponse
+	Downloadable bool
+	// Authenticated operations need an API token, or a signed-in session for
+	// GET and HEAD.
+	Authenticated bool
+}
+
+type apiResponse struct {
+	Description string
+	Schema      *apiSchema
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func ref(name string) *apiSchema {
+	return &apiSchema{Ref: "#/components/schemas/" + name}
+}
+
+func envelopeOf(key string, schema *apiSchema) *apiSchema {
+	return &apiSchema{
+		Type:       "object",
+		Properties: map[string]apiSchema{key: *schema},
+	}
+}
+
+var (
+	idPathParam = apiParam{Name: "id", In: "path", Required: true, Schema: apiSchema{Type: "integer", Minimum: ptr(1.0)}}
+
+	formatQueryParam = apiParam{
+		Name:        "format",
+		In:          "query",
+		Description: "Response format. Overrides the Accept header.",
+		Schema:      apiSchema{Type: "string", Enum: []string{formatJSON, formatCSV, formatNDJSON}},
+	}
+
+	errorResponses = map[int]apiResponse{
+		http.StatusNotModified:         {Description: "The cached representation named by If-None-Match or If-Modified-Since is current"},
+		http.StatusUnauthorized:        {Description: "The request needs a valid API token or a signed-in session", Schema: ref("Error")},
+		http.StatusForbidden:           {Description: "The API token is read-only, the account isn't activated, or its role doesn't permit this", Schema: ref("Error")},
+		http.StatusNotFound:            {Description: "The resource could not be found", Schema: ref("Error")},
+		http.StatusNotAcceptable:       {Description: "The requested format is not supported", Schema: ref("Error")},
+		http.StatusUnprocessableEntity: {Description: "The request failed validation", Schema: ref("ValidationError")},
+		http.StatusTooManyRequests:     {Description: "The client has exceeded its rate limit", Schema: ref("Error")},
+		http.StatusInternalServerError: {Description: "The server encountered a problem", Schema: ref("Error")},
+	}
+)
+
+func filterParam(name, description string) apiParam {
+	return apiParam{Name: name, In: "query", Description: description, Schema: apiSchema{Type: "integer"}}
+}
+
+func withErrors(responses map[int]apiResponse, codes ...int) map[int]apiResponse {
+	for _, code := range codes {
+		responses[code] = errorResponses[code]
+	}
+	return responses
+}
+
+// apiOperations lists the JSON API. Browser pages and the OAuth redirect endpoints
+// are left out on purpose.
+var apiOperations = []apiOperation{
+	{
+		Method:  http.MethodGet,
+		Path:    "/v1/healthcheck",
+		Summary: "Report application status",
+		Tag:     "system",
+		Responses: map[int]apiResponse{
+			http.StatusOK: {Description: "Application status", Schema: &apiSchema{
+				Type: "object",
+				Properties: map[string]apiSchema{
+					"status":      {Type: "string"},
+					"system_info": {Type: "object", Properties: map[string]apiSchema{"environment": {Type: "string"}, "version": {Type: "string"}}},
+				},
+			}},
+		},
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/v1/health/live",
+		Summary: "Liveness probe",
+		Tag:     "system",
+		Responses: map[int]apiResponse{
+			http.StatusOK: {Description: "The process is serving requests", Schema: &apiSchema{
+				Type:       "object",
+				Properties: map[string]apiSchema{"status": {Type: "string"}},
+			}},
+		},
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/v1/health/ready",
+		Summary: "Readiness probe with dependency checks",
+		Tag:     "system",
+		Responses: map[int]apiResponse{
+			http.StatusOK:                 {Description: "All required dependencies are up", Schema: ref("Readiness")},
+			http.StatusServiceUnavailable: {Description: "A required dependency is down", Schema: ref("Readiness")},
+		},
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/v1/leagues",
+		Summary: "List leagues",
+		Tag:     "archive",
+		Params: []apiParam{
+			{Name: "page", In: "query", Schema: apiSchema{Type: "integer", Minimum: ptr(1.0), Maximum: ptr(10_000_000.0)}},
+			{Name: "page_size", In: "query", Schema: apiSchema{Type: "integer", Minimum: ptr(1.0), Maximum: ptr(100.0)}},
+			{Name: "sort", In: "query", Schema: apiSchema{Type: "string", Enum: []string{"id", "nflWeek", "year", "currentWeek", "teamCount", "-id", "-year", "-teamCount"}}},
+			filterParam("id", "Only the league with this ID"),
+			filterParam("leagueId", "Only leagues with this ESPN league ID"),
+			filterParam("year", "Only leagues for this season"),
+			filterParam("teamCount", "Only leagues with this many teams"),
+			filterParam("currentWeek", "Only leagues at this week"),
+			filterParam("nflWeek", "Only leagues at this NFL week"),
+			formatQueryParam,
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "A page of leagues", Schema: envelopeOf("leagues", &apiSchema{Type: "array", Items: ref("League")})},
+		}, http.StatusNotModified, http.StatusNotAcceptable, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusTooManyRequests),
+		Downloadable: true,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/v1/leagues/:id",
+		Summary: "Show a league",
+		Tag:     "archive",
+		Params:  []apiParam{idPathParam, formatQueryParam},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "The league", Schema: envelopeOf("league", ref("League"))},
+		}, http.StatusNotModified, http.StatusNotFound, http.StatusNotAcceptable, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusTooManyRequests),
+		Downloadable: true,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/v1/leagues/:id/teams/:id",
+		Summary: "Show a team",
+		Tag:     "archive",
+		Params:  []apiParam{idPathParam, formatQueryParam},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "The team", Schema: envelopeOf("team", ref("TeamSummary"))},
+		}, http.StatusNotModified, http.StatusNotFound, http.StatusNotAcceptable, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusTooManyRequests),
+		Downloadable: true,
+	},
+	{
+		Method:        http.MethodGet,
+		Path:          "/v1/leagues/:id/roles",
+		Summary:       "List who holds which role in a league",
+		Tag:           "roles",
+		Authenticated: true,
+		Params:        []apiParam{idPathParam},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "The league's members, oldest first; email addresses are only shown to commissioners and admins", Schema: envelopeOf("members", &apiSchema{Type: "array", Items: ref("LeagueMember")})},
+		}, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError),
+	},
+	{
+		Method:        http.MethodPost,
+		Path:          "/v1/leagues/:id/roles",
+		Summary:       "Grant a user a role in a league",
+		Tag:           "roles",
+		Authenticated: true,
+		Params:        []apiParam{idPathParam},
+		Body: &apiSchema{
+			Type:     "object",
+			Required: []string{"user_id", "role"},
+			Properties: map[string]apiSchema{
+				"user_id": {Type: "integer", Format: "int64", Minimum: ptr(1.0)},
+				"role":    {Type: "string", Enum: data.Roles, Description: "Replaces any role the user holds; commissioners can only grant member and viewer"},
+			},
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "The role granted", Schema: envelopeOf("role", ref("LeagueRole"))},
+		}, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusTooManyRequests, http.StatusInternalServerError),
+	},
+	{
+		Method:        http.MethodDelete,
+		Path:          "/v1/leagues/:id/roles/:user_id",
+		Summary:       "Revoke a user's role in a league",
+		Tag:           "roles",
+		Authenticated: true,
+		Params: []apiParam{
+			idPathParam,
+			{Name: "user_id", In: "path", Required: true, Schema: apiSchema{Type: "integer", Minimum: ptr(1.0)}},
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "The role was revoked", Schema: ref("Message")},
+		}, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError),
+	},
+	{
+		Method:        http.MethodPost,
+		Path:          "/v1/leagues/:id/claims",
+		Summary:       "Claim an owner or team in a league, for a commissioner to approve",
+		Tag:           "claims",
+		Authenticated: true,
+		Params:        []apiParam{idPathParam},
+		Body: &apiSchema{
+			Type: "object",
+			Properties: map[string]apiSchema{
+				"owner":   {Type: "string", Description: "An owner name from the league's teams; give this or team_id", MaxLength: ptr(50)},
+				"team_id": {Type: "integer", Description: "An ESPN team ID in the league; give this or owner", Minimum: ptr(1.0)},
+				"message": {Type: "string", Description: "A note for the commissioner", MaxLength: ptr(500)},
+			},
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusCreated: {Description: "The pending claim", Schema: envelopeOf("claim", ref("TeamClaim"))},
+		}, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusTooManyRequests, http.StatusInternalServerError),
+	},
+	{
+		Method:        http.MethodGet,
+		Path:          "/v1/leagues/:id/claims",
+		Summary:       "List the team claims made in a league",
+		Tag:           "claims",
+		Authenticated: true,
+		Params: []apiParam{
+			idPathParam,
+			{Name: "status", In: "query", Description: "Only claims with this status", Schema: apiSchema{Type: "string", Enum: []string{data.ClaimPending, data.ClaimApproved, data.ClaimRejected, data.ClaimRevoked, data.ClaimWithdrawn}}},
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "The league's claims, newest first", Schema: envelopeOf("claims", &apiSchema{Type: "array", Items: ref("TeamClaim")})},
+		}, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusInternalServerError),
+	},
+	{
+		Method:        http.MethodGet,
+		Path:          "/v1/leagues/:id/claims/:claim_id",
+		Summary:       "Show a team claim and its history",
+		Tag:           "claims",
+		Authenticated: true,
+		Params:        []apiParam{idPathParam, {Name: "claim_id", In: "path", Required: true, Schema: apiSchema{Type: "integer", Minimum: ptr(1.0)}}},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "The claim and every change made to it, oldest first", Schema: &apiSchema{
+				Type: "object",
+				Properties: map[string]apiSchema{
+					"claim":   *ref("TeamClaim"),
+					"history": {Type: "array", Items: ref("TeamClaimEvent")},
+				},
+			}},
+		}, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError),
+	},
+	{
+		Method:        http.MethodPut,
+		Path:          "/v1/leagues/:id/claims/:claim_id",
+		Summary:       "Approve or reject a pending team claim, or revoke an approved one",
+		Tag:           "claims",
+		Authenticated: true,
+		Params:        []apiParam{idPathParam, {Name: "claim_id", In: "path", Required: true, Schema: apiSchema{Type: "integer", Minimum: ptr(1.0)}}},
+		Body: &apiSchema{
+			Type:     "object",
+			Required: []string{"status"},
+			Properties: map[string]apiSchema{
+				"status": {Type: "string", Enum: []string{data.ClaimApproved, data.ClaimRejected, data.ClaimRevoked}},
+				"note":   {Type: "string", Description: "Kept in the claim's history", MaxLength: ptr(500)},
+			},
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK:       {Description: "The updated claim", Schema: envelopeOf("claim", ref("TeamClaim"))},
+			http.StatusConflict: {Description: "The claim has already been decided or withdrawn", Schema: ref("Error")},
+		}, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusTooManyRequests, http.StatusInternalServerError),
+	},
+	{
+		Method:        http.MethodPost,
+		Path:          "/v1/leagues/:id/invitations",
+		Summary:       "Invite an email address to join a league",
+		Tag:           "invitations",
+		Authenticated: true,
+		Params:        []apiParam{idPathParam},
+		Body: &apiSchema{
+			Type:     "object",
+			Required: []string{"email"},
+			Properties: map[string]apiSchema{
+				"email":           {Type: "string", Format: "email"},
+				"role":            {Type: "string", Enum: data.Roles, Description: "Defaults to member; commissioners can only invite members and viewers"},
+				"owner":           {Type: "string", Description: "An owner name from the league's teams to link the invitee to", MaxLength: ptr(50)},
+				"team_id":         {Type: "integer", Description: "An ESPN team ID in the league to link the invitee to", Minimum: ptr(1.0)},
+				"expires_in_days": {Type: "integer", Description: "Days until the invitation expires; defaults to 7", Minimum: ptr(1.0), Maximum: ptr(30.0)},
+			},
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusCreated: {Description: "The invitation, which has been emailed", Schema: envelopeOf("invitation", ref("Invitation"))},
+		}, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusTooManyRequests, http.StatusInternalServerError),
+	},
+	{
+		Method:        http.MethodGet,
+		Path:          "/v1/leagues/:id/invitations",
+		Summary:       "List the invitations sent for a league",
+		Tag:           "invitations",
+		Authenticated: true,
+		Params:        []apiParam{idPathParam},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "The league's invitations, newest first", Schema: envelopeOf("invitations", &apiSchema{Type: "array", Items: ref("Invitation")})},
+		}, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError),
+	},
+	{
+		Method:        http.MethodDelete,
+		Path:          "/v1/leagues/:id/invitations/:invitation_id",
+		Summary:       "Revoke an invitation that hasn't been accepted",
+		Tag:           "invitations",
+		Authenticated: true,
+		Params: []apiParam{
+			idPathParam,
+			{Name: "invitation_id", In: "path", Required: true, Schema: apiSchema{Type: "integer", Minimum: ptr(1.0)}},
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "The invitation was revoked", Schema: ref("Message")},
+		}, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError),
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/v1/graphql",
+		Summary: "Run a GraphQL query",
+		Tag:     "archive",
+		Params: []apiParam{
+			{Name: "query", In: "query", Required: true, Schema: apiSchema{Type: "string"}},
+			{Name: "operationName", In: "query", Schema: apiSchema{Type: "string"}},
+			{Name: "variables", In: "query", Description: "JSON-encoded object", Schema: apiSchema{Type: "string"}},
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "GraphQL result", Schema: ref("GraphQLResult")},
+		}, http.StatusUnprocessableEntity, http.StatusTooManyRequests),
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/v1/graphql",
+		Summary: "Run a GraphQL query",
+		Tag:     "archive",
+		Body: &apiSchema{
+			Type:     "object",
+			Required: []string{"query"},
+			Properties: map[string]apiSchema{
+				"query":         {Type: "string"},
+				"operationName": {Type: "string", Nullable: true},
+				"variables":     {Type: "object", Nullable: true},
+			},
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "GraphQL result", Schema: ref("GraphQLResult")},
+		}, http.StatusUnprocessableEntity, http.StatusTooManyRequests),
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/v1/users",
+		Summary: "Register a user",
+		Tag:     "users",
+		Body: &apiSchema{
+			Type:     "object",
+			Required: []string{"name", "email", "password"},
+			Properties: map[string]apiSchema{
+				"name":     {Type: "string", MinLength: ptr(1), MaxLength: ptr(500)},
+				"email":    {Type: "string", Format: "email"},
+				"password": {Type: "string", Format: "password", MinLength: ptr(8), MaxLength: ptr(72)},
+			},
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusAccepted: {Description: "The registered user, who is sent an activation token by email", Schema: envelopeOf("user", ref("User"))},
+		}, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusTooManyRequests),
+	},
+	{
+		Method:  http.MethodPut,
+		Path:    "/v1/users/activated",
+		Summary: "Activate a registered user",
+		Tag:     "users",
+		Body: &apiSchema{
+			Type:       "object",
+			Required:   []string{"token"},
+			Properties: map[string]apiSchema{"token": {Type: "string", Description: "The activation token from the welcome email", MinLength: ptr(26), MaxLength: ptr(26)}},
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "The activated user", Schema: envelopeOf("user", ref("User"))},
+		}, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusTooManyRequests),
+	},
+	{
+		Method:  http.MethodPut,
+		Path:    "/v1/users/password",
+		Summary: "Set a new password with a password reset token",
+		Tag:     "users",
+		Body: &apiSchema{
+			Type:     "object",
+			Required: []string{"password", "token"},
+			Properties: map[string]apiSchema{
+				"password": {Type: "string", Format: "password", MinLength: ptr(8), MaxLength: ptr(72)},
+				"token":    {Type: "string", Description: "The token from the password reset email", MinLength: ptr(26), MaxLength: ptr(26)},
+			},
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "The password was reset and every session of the user was signed out", Schema: ref("Message")},
+		}, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusTooManyRequests),
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/v1/login",
+		Summary: "Sign in with an email address and password",
+		Tag:     "users",
+		Body: &apiSchema{
+			Type:     "object",
+			Required: []string{"email", "password"},
+			Properties: map[string]apiSchema{
+				"email":    {Type: "string", Format: "email"},
+				"password": {Type: "string", Format: "password", MinLength: ptr(8), MaxLength: ptr(72)},
+				"remember": {Type: "boolean", Description: "Keep the session signed in for the remember-me lifetime rather than the usual one"},
+			},
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "The signed-in user; the response sets the session cookie", Schema: envelopeOf("user", ref("User"))},
+		}, http.StatusUnauthorized, http.StatusForbidden, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusTooManyRequests),
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/v1/login/magic",
+		Summary: "Email a single-use sign-in link",
+		Tag:     "users",
+		Body: &apiSchema{
+			Type:       "object",
+			Required:   []string{"email"},
+			Properties: map[string]apiSchema{"email": {Type: "string", Format: "email"}},
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusAccepted: {Description: "The link was sent; following it signs in, or creates, the user with that address", Schema: ref("Message")},
+		}, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusTooManyRequests),
+	},
+	{
+		Method:        http.MethodGet,
+		Path:          "/v1/tokens",
+		Summary:       "List your personal API tokens",
+		Tag:           "tokens",
+		Authenticated: true,
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "Your tokens, newest first", Schema: envelopeOf("tokens", &apiSchema{Type: "array", Items: ref("APIToken")})},
+		}, http.StatusUnauthorized, http.StatusInternalServerError),
+	},
+	{
+		Method:        http.MethodPost,
+		Path:          "/v1/tokens",
+		Summary:       "Create a personal API token",
+		Tag:           "tokens",
+		Authenticated: true,
+		Body: &apiSchema{
+			Type:     "object",
+			Required: []string{"name"},
+			Properties: map[string]apiSchema{
+				"name":            {Type: "string", MinLength: ptr(1), MaxLength: ptr(100)},
+				"expires_in_days": {Type: "integer", Description: "Days until the token expires; 0 or absent for never", Minimum: ptr(0.0), Maximum: ptr(365.0)},
+				"read_only":       {Type: "boolean", Description: "Limit the token to GET and HEAD requests"},
+			},
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusCreated: {Description: "The new token, including its plaintext, which is not shown again", Schema: envelopeOf("token", ref("APIToken"))},
+		}, http.StatusUnauthorized, http.StatusForbidden, http.StatusUnprocessableEntity, http.StatusTooManyRequests, http.StatusInternalServerError),
+	},
+	{
+		Method:        http.MethodDelete,
+		Path:          "/v1/tokens/:id",
+		Summary:       "Revoke a personal API token",
+		Tag:           "tokens",
+		Authenticated: true,
+		Params:        []apiParam{idPathParam},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "The token was revoked", Schema: ref("Message")},
+		}, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError),
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/v1/tokens/password-reset",
+		Summary: "Email a password reset token",
+		Tag:     "tokens",
+		Body: &apiSchema{
+			Type:       "object",
+			Required:   []string{"email"},
+			Properties: map[string]apiSchema{"email": {Type: "string", Format: "email"}},
+		},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusAccepted: {Description: "Accepted whether or not the address is registered", Schema: ref("Message")},
+		}, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusTooManyRequests),
+	},
+	{
+		Method:        http.MethodGet,
+		Path:          "/v1/claims",
+		Summary:       "List your team claims",
+		Tag:           "claims",
+		Authenticated: true,
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK: {Description: "Your claims in every league, newest first", Schema: envelopeOf("claims", &apiSchema{Type: "array", Items: ref("TeamClaim")})},
+		}, http.StatusUnauthorized, http.StatusInternalServerError),
+	},
+	{
+		Method:        http.MethodDelete,
+		Path:          "/v1/claims/:id",
+		Summary:       "Withdraw one of your pending or approved team claims",
+		Tag:           "claims",
+		Authenticated: true,
+		Params:        []apiParam{idPathParam},
+		Responses: withErrors(map[int]apiResponse{
+			http.StatusOK:       {Description: "The withdrawn claim", Schema: envelopeOf("claim", ref("TeamClaim"))},
+			http.StatusConflict: {Description: "The claim is no longer open", Schema: ref("Error")},
+		}, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError),
+	},
+}
+
+// openAPIDocument assembles the OpenAPI 3 document from apiOperations. Response
+// models are derived from the Go types the handlers encode, so field names follow
+// their JSON tags.
+func openAPIDocument() map[string]any {
+	paths := make(map[string]map[string]any)
+	for _, op := range apiOperations {
+		path := toOpenAPIPath(op.Path)
+		if paths[path] == nil {
+			paths[path] = make(map[string]any)
+		}
+
+		responses := make(map[string]any, len(op.Responses))
+		for code, response := range op.Responses {
+			entry := map[string]any{"description": response.Description}
+			if response.Schema != nil {
+				content := map[string]any{"application/json": map[string]any{"schema": response.Schema}}
+				if op.Downloadable && code == http.StatusOK {
+					content[formatContentTypes[formatCSV]] = map[string]any{"schema": apiSchema{Type: "string"}}
+					content[formatContentTypes[formatNDJSON]] = map[string]any{"schema": apiSchema{Type: "string"}}
+				}
+				entry["content"] = content
+			}
+			responses[strconv.Itoa(code)] = entry
+		}
+
+		operation := map[string]any{
+			"summary":     op.Summary,
+			"operationId": operationID(op),
+			"tags":        []string{op.Tag},
+			"responses":   responses,
+		}
+		if len(op.Params) > 0 {
+			operation["parameters"] = op.Params
+		}
+		if op.Authenticated {
+			// Sessions are only accepted for reads; see requireUser.
+			security := []map[string][]string{{"bearerAuth": {}}}
+			if op.Method == http.MethodGet || op.Method == http.MethodHead {
+				security = append(security, map[string][]string{"sessionCookie": {}})
+			}
+			operation["security"] = security
+		}
+		if op.Body != nil {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/json": map[string]any{"schema": op.Body}},
+			}
+		}
+		paths[path][strings.ToLower(op.Method)] = operation
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "Fantasy Football Archive API",
+			"description": "Historical fantasy football league data.",
+			"version":     version,
+		},
+		"servers": []map[string]string{{"url": "/"}},
+		"paths":   paths,
+		"components": map[string]any{
+			"securitySchemes": map[string]any{
+				"bearerAuth":    map[string]string{"type": "http", "scheme": "bearer", "description": "A personal API token"},
+				"sessionCookie": map[string]string{"type": "apiKey", "in": "cookie", "name": "auth-session"},
+			},
+			"schemas": map[string]any{
+				"League":      schemaFromType(reflect.TypeOf(db.League{})),
+				"TeamSummary": schemaFromType(reflect.TypeOf(db.GetTeamByIdRow{})),
+				"User": apiSchema{
+					Type: "object",
+					Properties: map[string]apiSchema{
+						"id":         {Type: "integer", Format: "int64"},
+						"created_at": {Type: "string", Format: "date-time"},
+						"name":       {Type: "string"},
+						"email":      {Type: "string", Format: "email"},
+						"activated":  {Type: "boolean"},
+					},
+				},
+				"APIToken":       schemaFromType(reflect.TypeOf(data.APIToken{})),
+				"LeagueMember":   schemaFromType(reflect.TypeOf(data.LeagueMember{})),
+				"LeagueRole":     schemaFromType(reflect.TypeOf(db.LeagueRole{})),
+				"TeamClaim":      schemaFromType(reflect.TypeOf(data.TeamClaim{})),
+				"TeamClaimEvent": schemaFromType(reflect.TypeOf(data.TeamClaimEvent{})),
+				"Invitation":     schemaFromType(reflect.TypeOf(data.Invitation{})),
+				"Message": apiSchema{
+					Type:       "object",
+					Properties: map[string]apiSchema{"message": {Type: "string"}},
+				},
+				"Error": apiSchema{
+					Type:       "object",
+					Properties: map[string]apiSchema{"error": {Type: "string"}},
+				},
+				"ValidationError": apiSchema{
+					Type: "object",
+					Properties: map[string]apiSchema{
+						"error": {Type: "object", Description: "Validation messages keyed by field name"},
+					},
+				},
+				"Readiness": apiSchema{
+					Type: "object",
+					Properties: map[string]apiSchema{
+						"status":    {Type: "string", Enum: []string{"ready", "unavailable"}},
+						"checks":    {Type: "object", Description: "Check results keyed by dependency name", AdditionalProperties: ptr(schemaFromType(reflect.TypeOf(healthCheck{})))},
+						"providers": {Type: "array", Description: "State of each configured login provider", Items: ptr(schemaFromType(reflect.TypeOf(providerStatus{})))},
+					},
+				},
+				"GraphQLResult": apiSchema{
+					Type: "object",
+					Properties: map[string]apiSchema{
+						"data":   {Type: "object", Nullable: true},
+						"errors": {Type: "array", Items: &apiSchema{Type: "object"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func toOpenAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func operationID(op apiOperation) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(op.Method))
+	for _, segment := range strings.Split(op.Path, "/") {
+		segment = strings.TrimPrefix(segment, ":")
+		if segment == "" || segment == "v1" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(segment[:1]) + segment[1:])
+	}
+	return b.String()
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	nullStringType = reflect.TypeOf(sql.NullString{})
+)
+
+// schemaFromType describes a struct from its exported fields and JSON tags. The
+// sql.Null* types are encoding/json structs of their own, e.g. {"String": "",
+// "Valid": false}, and are described that way.
+func schemaFromType(t reflect.Type) apiSchema {
+	switch {
+	case t == timeType:
+		return apiSchema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Struct && strings.HasPrefix(t.Name(), "Null") && t.PkgPath() == nullStringType.PkgPath():
+		valueField := t.Field(0)
+		return apiSchema{
+			Type: "object",
+			Properties: map[string]apiSchema{
+				valueField.Name: schemaFromType(valueField.Type),
+				"Valid":         {Type: "boolean"},
+			},
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := schemaFromType(t.Elem())
+		schema.Nullable = true
+		return schema
+	case reflect.Int32:
+		return apiSchema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return apiSchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return apiSchema{Type: "number"}
+	case reflect.Bool:
+		return apiSchema{Type: "boolean"}
+	case reflect.String:
+		return apiSchema{Type: "string"}
+	case reflect.Slice:
+		items := schemaFromType(t.Elem())
+		return apiSchema{Type: "array", Items: &items}
+	case reflect.Struct:
+		schema := apiSchema{Type: "object", Properties: make(map[string]apiSchema)}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			schema.Properties[name] = schemaFromType(field.Type)
+		}
+		return schema
+	}
+	return apiSchema{}
+}
+
+func (app *application) openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	err := app.writeJSON(w, http.StatusOK, openAPIDocument(), nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *application) apiDocsHandler(w http.ResponseWriter, r *http.Request) {
+	err := templates.SwaggerUI("/v1/openapi.json").Render(r.Context(), w)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// apiDocsAssets serves Swagger UI's scripts and styles from the copy embedded in
+// the binary, rather than from a CDN on the same origin as the session cookie.
+var apiDocsAssets = http.StripPrefix("/v1/docs/assets", http.FileServer(http.FS(swaggerFiles.FS)))
+
+// undocumentedRoutes are the /v1 routes deliberately left out of the OpenAPI
+// document: the document itself and its viewer, and the pages and redirects only
+// browsers follow. Routes under a pattern ending in "*" are all covered.
+var undocumentedRoutes = []string{
+	"GET /v1/openapi.json",
+	"GET /v1/docs",
+	"GET /v1/docs/assets/*",
+	"GET /v1/invitations/accept",
+	"GET /v1/auth/*",
+	"GET /v1/account/link/confirm",
+	"POST /v1/account/link/confirm",
+	"GET /v1/login/magic",
+	"POST /v1/login/magic/verify",
+	"GET /v1/dashboard*",
+	"POST /v1/dashboard/*",
+	"DELETE /v1/dashboard/*",
+}
+
+// checkAPIRoutes reports operations in apiOperations which the router doesn't
+// serve, and /v1 routes it serves which aren't in apiOperations or
+// undocumentedRoutes, so the published document can't silently drift from
+// routes.go in either direction.
+func checkAPIRoutes(router *instrumentedRouter) error {
+	var errs []error
+	documented := make(map[string]bool, len(apiOperations))
+	for _, op := range apiOperations {
+		documented[op.Method+" "+op.Path] = true
+		path := strings.ReplaceAll(op.Path, ":id", "1")
+		if handle, _, _ := router.Lookup(op.Method, path); handle == nil {
+			errs = append(errs, fmt.Errorf("openapi: %s %s is documented but not routed", op.Method, op.Path))
+		}
+	}
+
+	for _, route := range router.routes {
+		method, path, _ := strings.Cut(route, " ")
+		if !strings.HasPrefix(path, "/v1/") || documented[route] || isUndocumentedRoute(route) {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("openapi: %s %s is routed but not documented", method, path))
+	}
+	return errors.Join(errs...)
+}
+
+func isUndocumentedRoute(route string) bool {
+	for _, pattern := range undocumentedRoutes {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(route, prefix) || route == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRequest checks query string parameters and JSON bodies against the
+// operation in apiOperations that matches the request, before the handler runs.
+// Requests for routes that aren't described are passed through untouched.
+func (app *application) validateRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op, ok := findAPIOperation(r.Method, r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		v := validator.New()
+		qs := r.URL.Query()
+		for _, param := range op.Params {
+			if param.In != "query" {
+				continue
+			}
+			value, present := qs[param.Name]
+			if !present || value[0] == "" {
+				v.Check(!param.Required, param.Name, "must be provided")
+				continue
+			}
+			validateValue(v, param.Name, value[0], param.Schema)
+		}
+
+		if op.Body != nil {
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1_048_576))
+			if err != nil {
+				app.badRequestResponse(w, r, err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			// Malformed JSON is left for readJSON in the handler to report, so its
+			// messages stay the same as before.
+			var decoded map[string]any
+			if json.Unmarshal(body, &decoded) == nil {
+				validateObject(v, "", decoded, *op.Body)
+			}
+		}
+
+		if !v.Valid() {
+			app.failedValidationResponse(w, r, v.Errors)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func findAPIOperation(method, path string) (apiOperation, bool) {
+	for _, op := range apiOperations {
+		if op.Method == method && matchRoutePattern(op.Path, path) {
+			return op, true
+		}
+	}
+	return apiOperation{}, false
+}
+
+// matchRoutePattern reports whether path matches an httprouter pattern made up of
+// static segments and ":name" parameters.
+func matchRoutePattern(pattern, path string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternSegments) != len(pathSegments) {
+		return false
+	}
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, ":") {
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// validateValue checks a query string value, which always arrives as a string.
+func validateValue(v *validator.Validator, key, raw string, schema apiSchema) {
+	switch schema.Type {
+	case "integer":
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			v.AddError(key, "must be an integer value")
+			return
+		}
+		checkRange(v, key, float64(n), schema)
+	case "number":
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			v.AddError(key, "must be a number")
+			return
+		}
+		checkRange(v, key, n, schema)
+	case "boolean":
+		if _, err := strconv.ParseBool(raw); err != nil {
+			v.AddError(key, "must be a boolean value")
+		}
+	default:
+		checkString(v, key, raw, schema)
+	}
+}
+
+// validateJSONValue checks a decoded JSON value. Numbers decode as float64.
+func validateJSONValue(v *validator.Validator, key string, value any, schema apiSchema) {
+	if value == nil {
+		v.Check(schema.Nullable, key, "must not be null")
+		return
+	}
+
+	switch schema.Type {
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != float64(int64(n)) {
+			v.AddError(key, "must be an integer value")
+			return
+		}
+		checkRange(v, key, n, schema)
+	case "number":
+		n, ok := value.(float64)
+		if !ok {
+			v.AddError(key, "must be a number")
+			return
+		}
+		checkRange(v, key, n, schema)
+	case "boolean":
+		_, ok := value.(bool)
+		v.Check(ok, key, "must be a boolean value")
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			v.AddError(key, "must be a string")
+			return
+		}
+		checkString(v, key, s, schema)
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			v.AddError(key, "must be an array")
+			return
+		}
+		if schema.Items != nil {
+			for i, item := range items {
+				validateJSONValue(v, fmt.Sprintf("%s[%d]", key, i), item, *schema.Items)
+			}
+		}
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			v.AddError(key, "must be an object")
+			return
+		}
+		validateObject(v, key+".", object, schema)
+	}
+}
+
+func validateObject(v *validator.Validator, prefix string, object map[string]any, schema apiSchema) {
+	for _, name := range schema.Required {
+		_, present := object[name]
+		v.Check(present, prefix+name, "must be provided")
+	}
+	for name, property := range schema.Properties {
+		if value, present := object[name]; present {
+			validateJSONValue(v, prefix+name, value, property)
+		}
+	}
+}
+
+func checkRange(v *validator.Validator, key string, n float64, schema apiSchema) {
+	if schema.Minimum != nil {
+		v.Check(n >= *schema.Minimum, key, "must be at least "+strconv.FormatFloat(*schema.Minimum, 'f', -1, 64))
+	}
+	if schema.Maximum != nil {
+		v.Check(n <= *schema.Maximum, key, "must be a maximum of "+strconv.FormatFloat(*schema.Maximum, 'f', -1, 64))
+	}
+}
+
+func checkString(v *validator.Validator, key, s string, schema apiSchema) {
+	if len(schema.Enum) > 0 {
+		v.Check(slices.Contains(schema.Enum, s), key, fmt.Sprintf("must be one of %s", strings.Join(schema.Enum, ", ")))
+	}
+	if schema.MinLength != nil {
+		v.Check(len(s) >= *schema.MinLength, key, fmt.Sprintf("must be at least %d bytes long", *schema.MinLength))
+	}
+	if schema.MaxLength != nil {
+		v.Check(len(s) <= *schema.MaxLength, key, fmt.Sprintf("must not be more than %d bytes long", *schema.MaxLength))
+	}
+	if schema.Format == "email" {
+		v.Check(validator.Matches(s, validator.EmailRX), key, "must be a valid email address")
+	}
+}
@@ -0,0 +1,510 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/layer8s/home-dashboard-app/internal/config"
+	"golang.org/x/oauth2"
+)
+
+// AuthProvider is a registered login provider. OIDC providers have an oidc.Provider
+// and a Verifier for their ID tokens; plain OAuth2 providers have neither, and
+// the user is read from UserInfoURL instead.
+type AuthProvider struct {
+	Name         string
+	Type         string
+	Provider     *oidc.Provider
+	Config       oauth2.Config
+	Verifier     *oidc.IDTokenVerifier
+	ClientID     string
+	ClientSecret string
+	Issuer       string
+	UserInfoURL  string
+	Claims       config.ClaimMapping
+	// EndSessionURL is where to send the user to sign them out of the provider as
+	// well; empty if the provider doesn't support RP-initiated logout.
+	EndSessionURL string
+}
+
+// newAuthProvider builds the provider declared by cfg. OIDC providers are
+// discovered from their issuer, which needs the provider to be reachable.
+func newAuthProvider(ctx context.Context, baseCallbackURL string, cfg config.Provider) (*AuthProvider, error) {
+	p := &AuthProvider{
+		Name:         cfg.Name,
+		Type:         cfg.Type,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		Issuer:       cfg.Issuer,
+		UserInfoURL:  cfg.UserInfoURL,
+		Claims:       cfg.Claims,
+		// Plain OAuth2 has no logout, so this is only set for them if configured.
+		EndSessionURL: cfg.EndSessionURL,
+		Config: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  fmt.Sprintf("%s/v1/auth/%s/callback", baseCallbackURL, cfg.Name),
+			Scopes:       cfg.Scopes,
+		},
+	}
+
+	switch cfg.Type {
+	case config.ProviderOIDC:
+		ctx = oidc.ClientContext(ctx, tracedHTTPClient())
+		provider, err := oidc.NewProvider(ctx, cfg.Issuer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize provider: %w", err)
+		}
+		p.Provider = provider
+		p.Verifier = provider.Verifier(&oidc.Config{ClientID: cfg.ClientID})
+		p.Config.Endpoint = provider.Endpoint()
+		p.Config.Scopes = append([]string{oidc.ScopeOpenID, "profile", "email"}, cfg.Scopes...)
+
+		var metadata struct {
+			EndSessionEndpoint string `json:"end_session_endpoint"`
+		}
+		if err := provider.Claims(&metadata); err != nil {
+			return nil, fmt.Errorf("reading provider metadata: %w", err)
+		}
+		p.EndSessionURL = cmp.Or(cfg.EndSessionURL, metadata.EndSessionEndpoint)
+	case config.ProviderOAuth2:
+		p.Config.Endpoint = oauth2.Endpoint{AuthURL: cfg.AuthURL, TokenURL: cfg.TokenURL}
+	default:
+		return nil, fmt.Errorf("unknown provider type %q", cfg.Type)
+	}
+
+	return p, nil
+}
+
+// Discovery of a provider that couldn't be set up is retried with exponential
+// backoff between these bounds, so an outage at one provider neither stops the
+// server starting nor has it hammering the provider.
+const (
+	providerRetryMin     = 2 * time.Second
+	providerRetryMax     = 5 * time.Minute
+	providerSetupTimeout = 10 * time.Second
+)
+
+var (
+	errProviderNotFound    = errors.New("provider not found")
+	errProviderUnavailable = errors.New("provider unavailable")
+)
+
+// providerEntry tracks a configured provider until, and after, it's set up.
+type providerEntry struct {
+	config      config.Provider
+	provider    *AuthProvider
+	err         error
+	attempts    int
+	lastAttempt time.Time
+	nextAttempt time.Time
+	// pending is closed when the attempt in progress finishes; nil when idle.
+	pending chan struct{}
+}
+
+// AuthManager holds the configured login providers. Providers are set up lazily:
+// the first request that needs one triggers discovery, and Run retries those that
+// failed in the background, so the server starts and serves the rest of the app
+// while a provider is down.
+type AuthManager struct {
+	baseCallbackURL string
+	logger          *slog.Logger
+	entries         map[string]*providerEntry
+	order           []string
+	mu              sync.Mutex
+}
+
+func NewAuthManager(baseCallbackURL string, logger *slog.Logger) *AuthManager {
+	return &AuthManager{
+		baseCallbackURL: baseCallbackURL,
+		logger:          logger,
+		entries:         make(map[string]*providerEntry),
+	}
+}
+
+// AddProvider declares a provider without contacting it.
+func (am *AuthManager) AddProvider(cfg config.Provider) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	if _, exists := am.entries[cfg.Name]; !exists {
+		am.order = append(am.order, cfg.Name)
+	}
+	am.entries[cfg.Name] = &providerEntry{config: cfg}
+}
+
+// Provider returns the named provider, setting it up first if that hasn't been
+// done and no retry is being waited out. It returns errProviderNotFound for
+// providers that aren't configured and errProviderUnavailable, wrapping the cause,
+// for those that can't currently be set up.
+func (am *AuthManager) Provider(ctx context.Context, name string) (*AuthProvider, error) {
+	am.mu.Lock()
+	entry, exists := am.entries[name]
+	if !exists {
+		am.mu.Unlock()
+		return nil, errProviderNotFound
+	}
+	if entry.provider != nil {
+		am.mu.Unlock()
+		return entry.provider, nil
+	}
+	pending := entry.pending
+	due := pending == nil && !time.Now().Before(entry.nextAttempt)
+	err := entry.err
+	am.mu.Unlock()
+
+	switch {
+	case pending != nil:
+		select {
+		case <-pending:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	case due:
+		am.setUp(ctx, name)
+	default:
+		return nil, fmt.Errorf("%w: %w", errProviderUnavailable, err)
+	}
+
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	if entry.provider == nil {
+		return nil, fmt.Errorf("%w: %w", errProviderUnavailable, entry.err)
+	}
+	return entry.provider, nil
+}
+
+// setUp makes one attempt at setting up the named provider, unless one is already
+// in progress, and schedules the next attempt if it fails.
+func (am *AuthManager) setUp(ctx context.Context, name string) {
+	am.mu.Lock()
+	entry := am.entries[name]
+	if entry.provider != nil || entry.pending != nil {
+		am.mu.Unlock()
+		return
+	}
+	pending := make(chan struct{})
+	entry.pending = pending
+	cfg := entry.config
+	am.mu.Unlock()
+
+	// The attempt is shared with any request that arrives while it runs, so it
+	// mustn't be cut short by the request that happened to start it.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), providerSetupTimeout)
+	provider, err := newAuthProvider(ctx, am.baseCallbackURL, cfg)
+	cancel()
+
+	am.mu.Lock()
+	entry.pending = nil
+	entry.attempts++
+	entry.lastAttempt = time.Now()
+	if err == nil {
+		entry.provider, entry.err = provider, nil
+	} else {
+		entry.err = err
+		entry.nextAttempt = entry.lastAttempt.Add(providerBackoff(entry.attempts))
+	}
+	attempts, next := entry.attempts, entry.nextAttempt
+	am.mu.Unlock()
+	close(pending)
+
+	if err != nil {
+		am.logger.Warn("auth provider unavailable", "provider", name, "attempts", attempts, "retry_at", next, "error", err)
+	} else {
+		am.logger.Info("auth provider ready", "provider", name, "attempts", attempts)
+	}
+}
+
+// providerBackoff doubles the delay with each failed attempt, with jitter so
+// several instances don't retry in step.
+func providerBackoff(attempts int) time.Duration {
+	d := providerRetryMin << min(attempts-1, 16)
+	d = min(d, providerRetryMax)
+	return d - time.Duration(rand.Int64N(int64(d)/5))
+}
+
+// Run sets up every provider and keeps retrying those that fail until they're all
+// available or ctx is cancelled.
+func (am *AuthManager) Run(ctx context.Context) {
+	for {
+		am.mu.Lock()
+		var due []string
+		var wait time.Duration = -1
+		now := time.Now()
+		for _, name := range am.order {
+			entry := am.entries[name]
+			if entry.provider != nil || entry.pending != nil {
+				continue
+			}
+			if delay := entry.nextAttempt.Sub(now); delay > 0 {
+				if wait < 0 || delay < wait {
+					wait = delay
+				}
+				continue
+			}
+			due = append(due, name)
+		}
+		am.mu.Unlock()
+
+		var wg sync.WaitGroup
+		for _, name := range due {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				am.setUp(ctx, name)
+			}()
+		}
+		wg.Wait()
+
+		if len(due) > 0 {
+			continue
+		}
+		if wait < 0 {
+			// Everything is set up, or being set up by a request.
+			am.mu.Lock()
+			remaining := slices.ContainsFunc(am.order, func(name string) bool { return am.entries[name].provider == nil })
+			am.mu.Unlock()
+			if !remaining {
+				return
+			}
+			wait = providerRetryMin
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Available lists the providers that are set up, in configuration order.
+func (am *AuthManager) Available() []string {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	var names []string
+	for _, name := range am.order {
+		if am.entries[name].provider != nil {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// providerStatus describes a configured provider for the readiness endpoint.
+type providerStatus struct {
+	Name        string     `json:"name"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	LastAttempt *time.Time `json:"last_attempt,omitempty"`
+	NextAttempt *time.Time `json:"next_attempt,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	issuer      string
+	discovered  bool
+}
+
+// Status reports the state of every configured provider, in configuration order.
+// A provider is "available" once set up, "pending" before its first attempt or
+// while one is in progress, and "unavailable" while waiting to retry.
+func (am *AuthManager) Status() []providerStatus {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	statuses := make([]providerStatus, 0, len(am.order))
+	for _, name := range am.order {
+		entry := am.entries[name]
+		status := providerStatus{
+			Name:       name,
+			Type:       entry.config.Type,
+			Status:     "pending",
+			Attempts:   entry.attempts,
+			issuer:     entry.config.Issuer,
+			discovered: entry.provider != nil && entry.provider.Provider != nil,
+		}
+		if entry.attempts > 0 {
+			last := entry.lastAttempt
+			status.LastAttempt = &last
+		}
+		switch {
+		case entry.provider != nil:
+			status.Status = "available"
+		case entry.pending != nil:
+		case entry.err != nil:
+			next := entry.nextAttempt
+			status.Status, status.NextAttempt, status.Error = "unavailable", &next, strings.TrimSpace(entry.err.Error())
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// userIdentity is what a provider tells us about the user who signed in.
+type userIdentity struct {
+	Subject  string
+	Email    string
+	Name     string
+	Username string
+	// EmailVerified is set when the provider vouches for Email.
+	EmailVerified bool
+	// IDToken is the raw ID token from OIDC providers, kept to pass as the
+	// id_token_hint when signing the user out.
+	IDToken string
+}
+
+// identity reads the signed-in user from token. For OIDC providers the claims come
+// from the verified ID token, which must carry nonce, topped up from the userinfo
+// endpoint when the token leaves out a mapped claim, as Authelia does by default.
+// For OAuth2 providers they come from the userinfo endpoint alone.
+func (p *AuthProvider) identity(ctx context.Context, token *oauth2.Token, nonce string) (userIdentity, error) {
+	claims := make(map[string]any)
+
+	if p.Verifier != nil {
+		rawIDToken, ok := token.Extra("id_token").(string)
+		if !ok {
+			return userIdentity{}, errors.New("no id_token in token response")
+		}
+		idToken, err := p.Verifier.Verify(ctx, rawIDToken)
+		if err != nil {
+			return userIdentity{}, err
+		}
+		if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(nonce)) != 1 {
+			return userIdentity{}, errors.New("id_token nonce does not match the login attempt")
+		}
+		if err := idToken.Claims(&claims); err != nil {
+			return userIdentity{}, err
+		}
+
+		if p.missingClaims(claims) && p.Provider.UserInfoEndpoint() != "" {
+			info, err := p.Provider.UserInfo(ctx, oauth2.StaticTokenSource(token))
+			if err != nil {
+				return userIdentity{}, fmt.Errorf("fetching userinfo: %w", err)
+			}
+			var extra map[string]any
+			if err := info.Claims(&extra); err != nil {
+				return userIdentity{}, err
+			}
+			for name, value := range extra {
+				if _, ok := claims[name]; !ok {
+					claims[name] = value
+				}
+			}
+		}
+	} else {
+		if err := p.fetchUserInfo(ctx, token, &claims); err != nil {
+			return userIdentity{}, err
+		}
+	}
+
+	id := userIdentity{
+		Subject:  claimString(claims, p.Claims.Subject),
+		Email:    claimString(claims, p.Claims.Email),
+		Name:     claimString(claims, p.Claims.Name),
+		Username: claimString(claims, p.Claims.Username),
+	}
+	// A verification flag means nothing without an address to go with it.
+	id.EmailVerified = id.Email != "" && claimBool(claims, p.Claims.EmailVerified)
+	id.IDToken, _ = token.Extra("id_token").(string)
+	if id.Subject == "" {
+		return userIdentity{}, fmt.Errorf("provider %s returned no %q claim", p.Name, p.Claims.Subject)
+	}
+	if id.Name == "" {
+		id.Name = cmp.Or(id.Username, id.Email)
+	}
+	return id, nil
+}
+
+func (p *AuthProvider) missingClaims(claims map[string]any) bool {
+	for _, path := range []string{p.Claims.Subject, p.Claims.Email, p.Claims.Name, p.Claims.Username} {
+		if path != "" && claimString(claims, path) == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchUserInfo decodes the JSON document at the provider's userinfo URL into dst,
+// authenticating with the access token.
+func (p *AuthProvider) fetchUserInfo(ctx context.Context, token *oauth2.Token, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.UserInfoURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.Config.Client(ctx, token).Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching userinfo: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching userinfo: unexpected status %s", res.Status)
+	}
+
+	dec := json.NewDecoder(io.LimitReader(res.Body, 1<<20))
+	dec.UseNumber()
+	return dec.Decode(dst)
+}
+
+// claimValue looks up the claim at path, following dots into nested objects. It
+// returns nil if there's no such claim.
+func claimValue(claims map[string]any, path string) any {
+	if path == "" {
+		return nil
+	}
+
+	var value any = claims
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
+
+// claimString returns the claim at path as a string. Numeric IDs, such as
+// GitHub's, are formatted without an exponent; anything else that isn't a string
+// is treated as missing.
+func claimString(claims map[string]any, path string) string {
+	switch v := claimValue(claims, path).(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// claimBool returns the claim at path as a bool. Some providers send
+// email_verified as the string "true", so that's accepted too.
+func claimBool(claims map[string]any, path string) bool {
+	switch v := claimValue(claims, path).(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
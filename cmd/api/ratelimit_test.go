@@ -0,0 +1,24 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/sessions"
+)
+
+func TestRateLimitClient(t *testing.T) {
+	app := &application{sessionStore: sessions.NewCookieStore([]byte("0123456789abcdef0123456789abcdef"))}
+
+	r := httptest.NewRequest("GET", "/v1/leagues", nil)
+	r.RemoteAddr = "203.0.113.7:5555"
+	r.Header.Set("Authorization", "Bearer made-up-token")
+	if got, want := app.rateLimitClient(r), "ip:203.0.113.7"; got != want {
+		t.Errorf("unauthenticated token: got %q, want %q", got, want)
+	}
+
+	r = app.contextSetUser(r, &authenticatedUser{ID: 3, TokenID: 42})
+	if got, want := app.rateLimitClient(r), "token:42"; got != want {
+		t.Errorf("authenticated token: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/layer8s/home-dashboard-app/internal/data"
+	"github.com/layer8s/home-dashboard-app/internal/db"
+	"github.com/layer8s/home-dashboard-app/internal/validator"
+	"github.com/lib/pq"
+)
+
+// isSiteAdmin reports whether userID has a verified address listed in auth.admins.
+func (app *application) isSiteAdmin(ctx context.Context, userID int64) (bool, error) {
+	if len(app.config.Auth.Admins) == 0 {
+		return false, nil
+	}
+	admin, err := app.queries.IsSiteAdmin(ctx, db.IsSiteAdminParams{UserID: userID, Emails: app.config.Auth.Admins})
+	if err != nil {
+		return false, fmt.Errorf("checking site admins: %w", err)
+	}
+	return admin, nil
+}
+
+// leagueRole returns the role userID holds in the ESPN league leagueID, or "" if
+// they hold none. Site administrators are admins of every league, and users with
+// an approved team claim are at least members.
+func (app *application) leagueRole(ctx context.Context, userID int64, leagueID int32) (string, error) {
+	admin, err := app.isSiteAdmin(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if admin {
+		return data.RoleAdmin, nil
+	}
+
+	role, err := app.queries.GetLeagueRole(ctx, db.GetLeagueRoleParams{UserID: userID, LeagueID: leagueID})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	if data.RoleAtLeast(role, data.RoleMember) {
+		return role, nil
+	}
+
+	// Managing a team in the league, as approved by a commissioner, makes a user
+	// a member of it for as long as the claim stands.
+	claimed, err := app.queries.HasApprovedTeamClaim(ctx, db.HasApprovedTeamClaimParams{UserID: userID, LeagueID: leagueID})
+	if err != nil {
+		return "", fmt.Errorf("checking team claims: %w", err)
+	}
+	if claimed {
+		return data.RoleMember, nil
+	}
+	return role, nil
+}
+
+// listLeagueRolesHandler lists who holds which role in the league. The members of
+// a league are private to it; only commissioners and admins see their addresses.
+func (app *application) listLeagueRolesHandler(w http.ResponseWriter, r *http.Request) {
+	access := contextGetLeagueAccess(r)
+
+	rows, err := app.queries.ListLeagueRoles(r.Context(), access.League.LeagueId)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	showEmail := data.RoleAtLeast(access.Role, data.RoleCommissioner)
+	members := make([]data.LeagueMember, 0, len(rows))
+	for _, row := range rows {
+		member := data.LeagueMember{
+			UserID:    row.UserID,
+			Name:      row.Name,
+			Role:      row.Role,
+			UpdatedAt: row.UpdatedAt,
+		}
+		if showEmail {
+			member.Email = row.Email.String
+		}
+		if row.GrantedBy.Valid {
+			member.GrantedBy = &row.GrantedBy.Int64
+		}
+		members = append(members, member)
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"members": members}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// grantLeagueRoleHandler gives a user a role in the league, replacing any role
+// they already hold there.
+func (app *application) grantLeagueRoleHandler(w http.ResponseWriter, r *http.Request) {
+	access := contextGetLeagueAccess(r)
+	user := contextGetUser(r)
+
+	var input struct {
+		UserID int64  `json:"user_id"`
+		Role   string `json:"role"`
+	}
+	if err := app.readJSON(w, r, &input); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	v.Check(input.UserID > 0, "user_id", "must be provided")
+	v.Check(input.UserID != user.ID, "user_id", "must not be your own")
+	data.ValidateRole(v, input.Role)
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	current, err := app.leagueRole(r.Context(), input.UserID, access.League.LeagueId)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if !data.CanAssignRole(access.Role, current, input.Role) {
+		app.notPermittedResponse(w, r)
+		return
+	}
+
+	role, err := app.queries.UpsertLeagueRole(r.Context(), db.UpsertLeagueRoleParams{
+		UserID:    input.UserID,
+		LeagueID:  access.League.LeagueId,
+		Role:      input.Role,
+		GrantedBy: sql.NullInt64{Int64: user.ID, Valid: true},
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			v.AddError("user_id", "no user has this ID")
+			app.failedValidationResponse(w, r, v.Errors)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	app.requestLogger(r).Info("league role granted", "league_id", role.LeagueID, "user_id", role.UserID, "role", role.Role, "granted_by", user.ID)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"role": role}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// revokeLeagueRoleHandler removes a user's role in the league. Roles held as a
+// site administrator come from the configuration and can't be revoked here.
+func (app *application) revokeLeagueRoleHandler(w http.ResponseWriter, r *http.Request) {
+	access := contextGetLeagueAccess(r)
+	user := contextGetUser(r)
+
+	userID, err := app.readNestedIDParam(r, "user_id")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	current, err := app.queries.GetLeagueRole(r.Context(), db.GetLeagueRoleParams{UserID: userID, LeagueID: access.League.LeagueId})
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		app.notFoundResponse(w, r)
+		return
+	case err != nil:
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if userID == user.ID || !data.CanAssignRole(access.Role, current, "") {
+		app.notPermittedResponse(w, r)
+		return
+	}
+
+	_, err = app.queries.DeleteLeagueRole(r.Context(), db.DeleteLeagueRoleParams{UserID: userID, LeagueID: access.League.LeagueId})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	app.requestLogger(r).Info("league role revoked", "league_id", access.League.LeagueId, "user_id", userID, "role", current, "revoked_by", user.ID)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "role revoked"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -1,24 +1,76 @@
 package main
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/layer8s/home-dashboard-app/internal/data"
+	"github.com/layer8s/home-dashboard-app/internal/gen/archive/v1/archivev1connect"
 )
 
-func (app *application) routes() http.Handler {
-	router := httprouter.New()
-	router.NotFound = http.HandlerFunc(app.notFoundResponse)                 // 404
-	router.MethodNotAllowed = http.HandlerFunc(app.methodNotAllowedResponse) // 405
+// routes builds the handler for every route. It fails if the OpenAPI document
+// describes a route that isn't registered, or a /v1 route is registered that it
+// doesn't describe.
+func (app *application) routes() (http.Handler, error) {
+	// Handlers registered on router are instrumented with their route pattern;
+	// anything that doesn't match a route is counted as "unmatched".
+	router := app.instrumentRouter(httprouter.New())
+	router.NotFound = app.metrics.instrument("unmatched", http.HandlerFunc(app.notFoundResponse))                 // 404
+	router.MethodNotAllowed = app.metrics.instrument("unmatched", http.HandlerFunc(app.methodNotAllowedResponse)) // 405
 
 	router.HandlerFunc(http.MethodGet, "/", app.loginHandler)
 	router.HandlerFunc(http.MethodGet, "/v1/healthcheck", app.healthcheckHandler)
-	router.HandlerFunc(http.MethodGet, "/v1/leagues", app.listLeaguesHandler)
-	router.HandlerFunc(http.MethodGet, "/v1/leagues/:id", app.showLeagueHandler)
-	router.HandlerFunc(http.MethodGet, "/v1/leagues/:id/teams/:id", app.showTeamHandler)
-	router.HandlerFunc(http.MethodGet, "/v1/auth/:provider/callback", app.HandleCallback)
+	router.HandlerFunc(http.MethodGet, "/v1/health/live", app.liveHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/health/ready", app.readyHandler)
+	router.Handler(http.MethodGet, "/metrics", app.metrics.handler())
+	router.HandlerFunc(http.MethodGet, "/v1/openapi.json", app.openAPIHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/docs", app.apiDocsHandler)
+	router.Handler(http.MethodGet, "/v1/docs/assets/*filepath", apiDocsAssets)
+	// The archive of leagues, teams and matchups is public and read-only, here and
+	// over GraphQL and Connect below, so it needs no league role. What is private
+	// to a league is who holds which role in it, its claims and its invitations.
+	router.HandlerFunc(http.MethodGet, "/v1/leagues", app.rateLimit("archive", app.listLeaguesHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/leagues/:id", app.rateLimit("archive", app.showLeagueHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/leagues/:id/teams/:id", app.rateLimit("archive", app.showTeamHandler))
+
+	// Who holds which role in a league is only visible to those with a role in it,
+	// and only commissioners and admins can change it.
+	router.HandlerFunc(http.MethodGet, "/v1/leagues/:id/roles", app.requireLeagueRole(data.RoleViewer, app.listLeagueRolesHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/leagues/:id/roles", app.rateLimit("auth", app.requireLeagueRole(data.RoleCommissioner, app.grantLeagueRoleHandler)))
+	router.HandlerFunc(http.MethodDelete, "/v1/leagues/:id/roles/:user_id", app.rateLimit("auth", app.requireLeagueRole(data.RoleCommissioner, app.revokeLeagueRoleHandler)))
+
+	// Anyone signed in can claim a team in a league; its commissioners decide.
+	router.HandlerFunc(http.MethodPost, "/v1/leagues/:id/claims", app.rateLimit("auth", app.requireLeagueRole("", app.createTeamClaimHandler)))
+	router.HandlerFunc(http.MethodGet, "/v1/leagues/:id/claims", app.requireLeagueRole(data.RoleCommissioner, app.listTeamClaimsHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/leagues/:id/claims/:claim_id", app.requireLeagueRole(data.RoleCommissioner, app.showTeamClaimHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/leagues/:id/claims/:claim_id", app.rateLimit("auth", app.requireLeagueRole(data.RoleCommissioner, app.decideTeamClaimHandler)))
+	// Commissioners invite people to their leagues by email.
+	router.HandlerFunc(http.MethodPost, "/v1/leagues/:id/invitations", app.rateLimit("auth", app.requireLeagueRole(data.RoleCommissioner, app.createInvitationHandler)))
+	router.HandlerFunc(http.MethodGet, "/v1/leagues/:id/invitations", app.requireLeagueRole(data.RoleCommissioner, app.listInvitationsHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/leagues/:id/invitations/:invitation_id", app.rateLimit("auth", app.requireLeagueRole(data.RoleCommissioner, app.revokeInvitationHandler)))
+	router.HandlerFunc(http.MethodGet, "/v1/invitations/accept", app.rateLimit("auth", app.acceptInvitationHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/claims", app.requireUser(app.listMyClaimsHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/claims/:id", app.rateLimit("auth", app.requireUser(app.withdrawClaimHandler)))
+
+	// GraphQL shares the public read access of the archive routes above, but a
+	// single query can cost much more, so it has its own, lower, limit.
+	router.HandlerFunc(http.MethodGet, "/v1/graphql", app.rateLimit("graphql", app.graphqlHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/graphql", app.rateLimit("graphql", app.graphqlHandler))
+
+	// The Connect service mirrors the read-only archive routes for typed clients.
+	// Connect allows GET for the side-effect-free procedures, so register both.
+	rpcPath, rpcHandler := archivev1connect.NewArchiveServiceHandler(&archiveServer{app: app})
+	router.HandlerFunc(http.MethodGet, rpcPath+"*procedure", app.rateLimit("archive", rpcHandler.ServeHTTP))
+	router.HandlerFunc(http.MethodPost, rpcPath+"*procedure", app.rateLimit("archive", rpcHandler.ServeHTTP))
+
+	router.HandlerFunc(http.MethodGet, "/v1/auth/:provider/callback", app.rateLimit("auth", app.HandleCallback))
 	router.HandlerFunc(http.MethodGet, "/v1/auth/:provider/logout", app.HandleLogout)
-	router.HandlerFunc(http.MethodGet, "/v1/auth/:provider", app.HandleAuth)
+	router.HandlerFunc(http.MethodGet, "/v1/auth/:provider", app.rateLimit("auth", app.HandleAuth))
+	router.HandlerFunc(http.MethodGet, "/v1/auth/:provider/link", app.rateLimit("auth", app.requireAuthenticated(app.HandleLink)))
+	router.HandlerFunc(http.MethodGet, "/v1/account/link/confirm", app.linkConfirmHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/account/link/confirm", app.rateLimit("auth", app.linkConfirmSubmitHandler))
 
 	// route with authentication middleware
 	router.HandlerFunc(http.MethodGet, "/v1/dashboard",
@@ -37,11 +89,61 @@ func (app *application) routes() http.Handler {
 	router.HandlerFunc(http.MethodGet, "/v1/dashboard/index",
 		app.requireAuthenticated(app.leaguesIndexHandler))
 
-	router.HandlerFunc(http.MethodPost, "/v1/users", app.registerUserHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/dashboard/tokens",
+		app.requireAuthenticated(app.dashboardCreateTokenHandler))
+
+	router.HandlerFunc(http.MethodDelete, "/v1/dashboard/tokens/:id",
+		app.requireAuthenticated(app.dashboardRevokeTokenHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/dashboard/sessions",
+		app.requireAuthenticated(app.dashboardSessionsHandler))
+
+	router.HandlerFunc(http.MethodDelete, "/v1/dashboard/sessions/:id",
+		app.requireAuthenticated(app.dashboardRevokeSessionHandler))
+
+	router.HandlerFunc(http.MethodPost, "/v1/dashboard/sessions/revoke-all",
+		app.requireAuthenticated(app.dashboardRevokeAllSessionsHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/dashboard/teams",
+		app.requireAuthenticated(app.dashboardTeamsHandler))
+
+	router.HandlerFunc(http.MethodPost, "/v1/dashboard/claims",
+		app.requireAuthenticated(app.dashboardCreateClaimHandler))
+
+	router.HandlerFunc(http.MethodDelete, "/v1/dashboard/claims/:id",
+		app.requireAuthenticated(app.dashboardWithdrawClaimHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/dashboard/claims/review",
+		app.requireAuthenticated(app.dashboardClaimReviewsHandler))
+
+	router.HandlerFunc(http.MethodPost, "/v1/dashboard/claims/:id/decision",
+		app.requireAuthenticated(app.dashboardDecideClaimHandler))
+
+	router.HandlerFunc(http.MethodPost, "/v1/users", app.rateLimit("auth", app.registerUserHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/users/activated", app.rateLimit("auth", app.activateUserHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/users/password", app.rateLimit("auth", app.updateUserPasswordHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/login", app.rateLimit("auth", app.passwordLoginHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/login/magic", app.rateLimit("magic_link", app.requestMagicLinkHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/login/magic", app.magicLinkHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/login/magic/verify", app.rateLimit("auth", app.magicLinkVerifyHandler))
+
+	// Personal API tokens, for the signed-in user or the owner of the token used.
+	router.HandlerFunc(http.MethodGet, "/v1/tokens", app.requireUser(app.listTokensHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/tokens", app.rateLimit("auth", app.requireUser(app.createTokenHandler)))
+	router.HandlerFunc(http.MethodDelete, "/v1/tokens/:id", app.rateLimit("auth", app.requireUser(app.deleteTokenHandler)))
+	router.HandlerFunc(http.MethodPost, "/v1/tokens/password-reset", app.rateLimit("auth", app.createPasswordResetTokenHandler))
 
 	router.HandlerFunc(http.MethodGet, "/login", app.loginTemplHandler)
 
 	router.HandlerFunc(http.MethodGet, "/mm", app.magicMirrorHandler)
 
-	return app.recoverPanic(router)
+	if err := checkAPIRoutes(router); err != nil {
+		return nil, fmt.Errorf("OpenAPI document is out of sync with routes: %w", err)
+	}
+
+	handler := app.authenticate(app.sessionActivity(app.validateRequest(router)))
+	if app.config.Log.AccessLog {
+		handler = app.logRequest(handler)
+	}
+	return traceRequests(app.requestID(app.recoverPanic(handler))), nil
 }
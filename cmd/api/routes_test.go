@@ -0,0 +1,68 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/layer8s/home-dashboard-app/internal/config"
+)
+
+// TestRoutes builds the router, which panics on conflicting routes and fails if
+// the OpenAPI document and the registered routes disagree.
+func TestRoutes(t *testing.T) {
+	cfg := config.Defaults("development")
+	app := &application{
+		config:  &cfg,
+		logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		metrics: newMetrics(nil, nil, nil),
+	}
+	if _, err := app.routes(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckAPIRoutes(t *testing.T) {
+	app := &application{metrics: newMetrics(nil, nil, nil)}
+	handler := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	documentedRouter := func() *instrumentedRouter {
+		router := app.instrumentRouter(httprouter.New())
+		for _, op := range apiOperations {
+			router.Handler(op.Method, op.Path, handler)
+		}
+		return router
+	}
+
+	router := documentedRouter()
+	router.Handler(http.MethodGet, "/v1/dashboard/extra", handler)
+	router.Handler(http.MethodGet, "/mm/extra", handler)
+	if err := checkAPIRoutes(router); err != nil {
+		t.Errorf("allow-listed and non-/v1 routes: %v", err)
+	}
+
+	router = documentedRouter()
+	router.Handler(http.MethodPost, "/v1/leagues/:id/secrets", handler)
+	err := checkAPIRoutes(router)
+	if err == nil || !strings.Contains(err.Error(), "POST /v1/leagues/:id/secrets is routed but not documented") {
+		t.Errorf("undocumented route: got %v", err)
+	}
+	// The same path with another method isn't covered by the documented one.
+	router = documentedRouter()
+	router.Handler(http.MethodPatch, "/v1/tokens/:id", handler)
+	if err := checkAPIRoutes(router); err == nil {
+		t.Error("undocumented method on a documented path: got no error")
+	}
+
+	router = app.instrumentRouter(httprouter.New())
+	for _, op := range apiOperations[1:] {
+		router.Handler(op.Method, op.Path, handler)
+	}
+	err = checkAPIRoutes(router)
+	if want := apiOperations[0].Path + " is documented but not routed"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("unrouted operation: got %v, want %q", err, want)
+	}
+}
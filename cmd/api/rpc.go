@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"connectrpc.com/connect"
+	"github.com/layer8s/home-dashboard-app/internal/data"
+	"github.com/layer8s/home-dashboard-app/internal/db"
+	archivev1 "github.com/layer8s/home-dashboard-app/internal/gen/archive/v1"
+	"github.com/layer8s/home-dashboard-app/internal/gen/archive/v1/archivev1connect"
+	"github.com/layer8s/home-dashboard-app/internal/validator"
+)
+
+// archiveServer implements the Connect ArchiveService on top of the same sqlc
+// queries used by the REST handlers. It speaks the Connect, gRPC and gRPC-Web
+// protocols; plain HTTP/2 gRPC clients reach it through the h2c handler in serve().
+type archiveServer struct {
+	archivev1connect.UnimplementedArchiveServiceHandler
+	app *application
+}
+
+func (s *archiveServer) GetLeague(ctx context.Context, req *connect.Request[archivev1.GetLeagueRequest]) (*connect.Response[archivev1.GetLeagueResponse], error) {
+	league, err := s.app.queries.GetLeagueById(ctx, req.Msg.GetId())
+	if err != nil {
+		return nil, s.rpcError(ctx, err)
+	}
+
+	return connect.NewResponse(&archivev1.GetLeagueResponse{League: leagueToProto(league)}), nil
+}
+
+func (s *archiveServer) ListLeagues(ctx context.Context, req *connect.Request[archivev1.ListLeaguesRequest]) (*connect.Response[archivev1.ListLeaguesResponse], error) {
+	msg := req.Msg
+
+	filters := data.Filters{
+		Page:         int(msg.GetPage()),
+		PageSize:     int(msg.GetPageSize()),
+		Sort:         msg.GetSort(),
+		SortSafelist: []string{"id", "nflWeek", "year", "currentWeek", "teamCount", "-id", "-year", "-teamCount"},
+	}
+	// Zero values mean "not set" in proto3, so fall back to the REST defaults.
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.PageSize == 0 {
+		filters.PageSize = 20
+	}
+	if filters.Sort == "" {
+		filters.Sort = "id"
+	}
+
+	v := validator.New()
+	if data.ValidateFilters(v, filters); !v.Valid() {
+		return nil, validationError(v)
+	}
+
+	params := db.GetLeaguesAscParams{
+		ID:          optionalFilter(msg.Id),
+		LeagueId:    optionalFilter(msg.LeagueId),
+		Year:        optionalFilter(msg.Year),
+		TeamCount:   optionalFilter(msg.TeamCount),
+		CurrentWeek: optionalFilter(msg.CurrentWeek),
+		NflWeek:     optionalFilter(msg.NflWeek),
+		Limit:       int32(filters.PageSize),
+		Offset:      int32((filters.Page - 1) * filters.PageSize),
+		Column9:     filters.SortColumn(),
+	}
+
+	var leagues []db.League
+	var err error
+	if filters.SortDirection() == "DESC" {
+		leagues, err = s.app.queries.GetLeaguesDesc(ctx, db.GetLeaguesDescParams(params))
+	} else {
+		leagues, err = s.app.queries.GetLeaguesAsc(ctx, params)
+	}
+	if err != nil {
+		return nil, s.rpcError(ctx, err)
+	}
+
+	res := &archivev1.ListLeaguesResponse{Leagues: make([]*archivev1.League, 0, len(leagues))}
+	for _, league := range leagues {
+		res.Leagues = append(res.Leagues, leagueToProto(league))
+	}
+	return connect.NewResponse(res), nil
+}
+
+func (s *archiveServer) GetTeam(ctx context.Context, req *connect.Request[archivev1.GetTeamRequest]) (*connect.Response[archivev1.GetTeamResponse], error) {
+	team, err := s.app.queries.GetTeamById(ctx, req.Msg.GetId())
+	if err != nil {
+		return nil, s.rpcError(ctx, err)
+	}
+
+	return connect.NewResponse(&archivev1.GetTeamResponse{Team: teamRowToProto(team)}), nil
+}
+
+func (s *archiveServer) ListTeams(ctx context.Context, req *connect.Request[archivev1.ListTeamsRequest]) (*connect.Response[archivev1.ListTeamsResponse], error) {
+	teams, err := s.app.queries.ListTeamsByLeagueIDs(ctx, []int32{req.Msg.GetLeagueId()})
+	if err != nil {
+		return nil, s.rpcError(ctx, err)
+	}
+
+	res := &archivev1.ListTeamsResponse{Teams: make([]*archivev1.Team, 0, len(teams))}
+	for _, team := range teams {
+		res.Teams = append(res.Teams, teamToProto(team))
+	}
+	return connect.NewResponse(res), nil
+}
+
+func (s *archiveServer) ListMatchups(ctx context.Context, req *connect.Request[archivev1.ListMatchupsRequest]) (*connect.Response[archivev1.ListMatchupsResponse], error) {
+	matchups, err := s.app.queries.ListMatchupsByTeamIDs(ctx, []int32{req.Msg.GetTeamId()})
+	if err != nil {
+		return nil, s.rpcError(ctx, err)
+	}
+
+	res := &archivev1.ListMatchupsResponse{Matchups: make([]*archivev1.Matchup, 0, len(matchups))}
+	for _, matchup := range matchups {
+		res.Matchups = append(res.Matchups, &archivev1.Matchup{
+			Id:          matchup.ID,
+			Week:        matchup.Week,
+			HomeTeamId:  nullInt32(matchup.HomeTeamID),
+			AwayTeamId:  nullInt32(matchup.AwayTeamID),
+			HomeScore:   nullFloat64(matchup.HomeScore),
+			AwayScore:   nullFloat64(matchup.AwayScore),
+			IsPlayoff:   nullBool(matchup.IsPlayoff),
+			MatchupType: nullString(matchup.MatchupType),
+		})
+	}
+	return connect.NewResponse(res), nil
+}
+
+// rpcError maps a database error to a Connect error, logging anything unexpected in
+// the same way serverErrorResponse does for the REST routes.
+func (s *archiveServer) rpcError(ctx context.Context, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return connect.NewError(connect.CodeNotFound, errors.New("the requested resource could not be found"))
+	}
+	s.app.loggerFromContext(ctx).Error(err.Error(), "protocol", "connect")
+	return connect.NewError(connect.CodeInternal, errors.New("the server encountered a problem and could not process your request"))
+}
+
+// validationError converts validator errors into an InvalidArgument error, with
+// the per-field messages attached as error metadata.
+func validationError(v *validator.Validator) error {
+	err := connect.NewError(connect.CodeInvalidArgument, errors.New("the request failed validation"))
+	for key, message := range v.Errors {
+		err.Meta().Add("x-validation-"+key, message)
+	}
+	return err
+}
+
+// optionalFilter turns an unset proto3 optional field into the -1 "no filter"
+// sentinel understood by the leagues queries.
+func optionalFilter(value *int32) int32 {
+	if value == nil {
+		return -1
+	}
+	return *value
+}
+
+func leagueToProto(league db.League) *archivev1.League {
+	return &archivev1.League{
+		Id:          league.ID,
+		LeagueId:    league.LeagueId,
+		Year:        league.Year,
+		TeamCount:   league.TeamCount,
+		CurrentWeek: league.CurrentWeek,
+		NflWeek:     league.NflWeek,
+	}
+}
+
+func teamToProto(team db.Team) *archivev1.Team {
+	return &archivev1.Team{
+		Id:                     team.ID,
+		LeagueId:               team.LeagueID,
+		TeamId:                 team.TeamId,
+		Year:                   team.Year,
+		TeamAbbrv:              team.TeamAbbrv,
+		TeamName:               team.TeamName,
+		Owners:                 nullString(team.Owners),
+		DivisionId:             nullString(team.DivisionId),
+		DivisionName:           nullString(team.DivisionName),
+		Wins:                   nullInt32(team.Wins),
+		Losses:                 nullInt32(team.Losses),
+		Ties:                   nullInt32(team.Ties),
+		PointsFor:              nullInt32(team.PointsFor),
+		PointsAgainst:          nullInt32(team.PointsAgainst),
+		WaiverRank:             nullInt32(team.WaiverRank),
+		Acquisitions:           nullInt32(team.Acquisitions),
+		AcquisitionBudgetSpent: nullInt32(team.AcquisitionBudgetSpent),
+		Drops:                  nullInt32(team.Drops),
+		Trades:                 nullInt32(team.Trades),
+		StreakType:             nullString(team.StreakType),
+		StreakLength:           nullInt32(team.StreakLength),
+		Standing:               nullInt32(team.Standing),
+		FinalStanding:          nullInt32(team.FinalStanding),
+		DraftProjRank:          nullInt32(team.DraftProjRank),
+		PlayoffPct:             nullInt32(team.PlayoffPct),
+		LogoUrl:                nullString(team.LogoUrl),
+	}
+}
+
+// teamRowToProto converts the narrower row returned by GetTeamById, which is what
+// GET /v1/leagues/:id/teams/:id serves; fields it doesn't select are left unset.
+func teamRowToProto(team db.GetTeamByIdRow) *archivev1.Team {
+	return &archivev1.Team{
+		Id:                     team.ID,
+		LeagueId:               team.LeagueID,
+		TeamId:                 team.TeamId,
+		Year:                   team.Year,
+		TeamAbbrv:              team.TeamAbbrv,
+		TeamName:               team.TeamName,
+		Owners:                 nullString(team.Owners),
+		DivisionId:             nullString(team.DivisionId),
+		DivisionName:           nullString(team.DivisionName),
+		Wins:                   nullInt32(team.Wins),
+		Losses:                 nullInt32(team.Losses),
+		Ties:                   nullInt32(team.Ties),
+		PointsFor:              nullInt32(team.PointsFor),
+		PointsAgainst:          nullInt32(team.PointsAgainst),
+		WaiverRank:             nullInt32(team.WaiverRank),
+		Acquisitions:           nullInt32(team.Acquisitions),
+		AcquisitionBudgetSpent: nullInt32(team.AcquisitionBudgetSpent),
+		Drops:                  nullInt32(team.Drops),
+		Trades:                 nullInt32(team.Trades),
+		LogoUrl:                nullString(team.LogoUrl),
+	}
+}
+
+func nullString(v sql.NullString) *string {
+	if !v.Valid {
+		return nil
+	}
+	return &v.String
+}
+
+func nullInt32(v sql.NullInt32) *int32 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int32
+}
+
+func nullFloat64(v sql.NullFloat64) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Float64
+}
+
+func nullBool(v sql.NullBool) *bool {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Bool
+}
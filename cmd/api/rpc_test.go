@@ -0,0 +1,17 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/layer8s/home-dashboard-app/internal/db"
+)
+
+func TestTeamRowToProto(t *testing.T) {
+	team := teamRowToProto(db.GetTeamByIdRow{ID: 7, LeagueID: 3, TeamId: 4, Year: 2023, TeamAbbrv: "ABC", TeamName: "The Abcs"})
+	if team.GetTeamId() != 4 || team.GetTeamName() != "The Abcs" {
+		t.Errorf("team_id = %d, team_name = %q", team.GetTeamId(), team.GetTeamName())
+	}
+	if team.Owners != nil || team.Wins != nil {
+		t.Errorf("NULL columns should be unset, got owners %v, wins %v", team.Owners, team.Wins)
+	}
+}
@@ -10,13 +10,22 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 func (app *application) serve() error {
-	// Declare a HTTP server
+	routes, err := app.routes()
+	if err != nil {
+		return err
+	}
+
+	// Declare a HTTP server. The h2c wrapper lets gRPC clients speak cleartext
+	// HTTP/2 to the Connect service without TLS in front of it.
 	srv := &http.Server{
-		Addr:         fmt.Sprintf(":%d", app.config.port),
-		Handler:      app.routes(),
+		Addr:         fmt.Sprintf(":%d", app.config.Port),
+		Handler:      h2c.NewHandler(routes, &http2.Server{}),
 		IdleTimeout:  time.Minute,
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
@@ -49,9 +58,9 @@ func (app *application) serve() error {
 		shutdownError <- nil
 	}()
 
-	app.logger.Info("starting server", "addr", srv.Addr, "env", app.config.env)
+	app.logger.Info("starting server", "addr", srv.Addr, "env", app.config.Env)
 
-	err := srv.ListenAndServe()
+	err = srv.ListenAndServe()
 	if !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
@@ -0,0 +1,436 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/gorilla/sessions"
+	"github.com/julienschmidt/httprouter"
+	"github.com/layer8s/home-dashboard-app/internal/data"
+	"github.com/layer8s/home-dashboard-app/templates"
+)
+
+const (
+	// sessionKeyPrefix is the prefix the session store keeps each session under in
+	// Redis, as session_<id>.
+	sessionKeyPrefix = "session_"
+	// sessionTouchInterval is how often a session's last-seen time is written
+	// back. Idle timeouts are checked against it, so may end a session up to this
+	// much early.
+	sessionTouchInterval = time.Minute
+)
+
+var errSessionNotTracked = errors.New("session not tracked")
+
+func userSessionsKey(userID int64) string {
+	return "user_sessions:" + strconv.FormatInt(userID, 10)
+}
+
+// sessionInfoKey holds what's known about a signed-in session: who it belongs
+// to, the device and IP it's used from, and when it was created, last used and
+// expires.
+func sessionInfoKey(sessionID string) string {
+	return "user_session:" + sessionID
+}
+
+// sessionLifetime is how long a session signed in now lasts.
+func (app *application) sessionLifetime(remember bool) time.Duration {
+	if remember && app.config.Session.RememberMeMaxAge > 0 {
+		return app.config.Session.RememberMeMaxAge
+	}
+	return app.config.Session.MaxAge
+}
+
+// trackSession records that the session sessionID has just signed in as userID,
+// so the user can see it and all of their sessions can be revoked together.
+func (app *application) trackSession(r *http.Request, userID int64, sessionID, provider string, remember bool) error {
+	ctx := r.Context()
+	now := time.Now()
+	expires := now.Add(app.sessionLifetime(remember))
+	key := userSessionsKey(userID)
+
+	_, err := app.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, sessionInfoKey(sessionID), map[string]any{
+			"user_id":    userID,
+			"provider":   provider,
+			"user_agent": r.UserAgent(),
+			"ip":         clientIP(r),
+			"created_at": now.Unix(),
+			"last_seen":  now.Unix(),
+			"expires_at": expires.Unix(),
+			"remember":   strconv.FormatBool(remember),
+		})
+		pipe.ExpireAt(ctx, sessionInfoKey(sessionID), expires)
+		pipe.SAdd(ctx, key, sessionID)
+		// The set lasts as long as the longest session could.
+		pipe.Expire(ctx, key, app.sessionLifetime(true))
+		return nil
+	})
+	return err
+}
+
+// sessionInfo returns what's tracked about the session sessionID, or
+// errSessionNotTracked if it isn't signed in.
+func (app *application) sessionInfo(ctx context.Context, sessionID string) (*data.Session, error) {
+	fields, err := app.redisClient.HGetAll(ctx, sessionInfoKey(sessionID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseSessionInfo(sessionID, fields)
+}
+
+func parseSessionInfo(sessionID string, fields map[string]string) (*data.Session, error) {
+	if len(fields) == 0 {
+		return nil, errSessionNotTracked
+	}
+
+	unix := func(name string) time.Time {
+		n, _ := strconv.ParseInt(fields[name], 10, 64)
+		return time.Unix(n, 0)
+	}
+	userID, err := strconv.ParseInt(fields["user_id"], 10, 64)
+	if err != nil {
+		return nil, errSessionNotTracked
+	}
+	remember, _ := strconv.ParseBool(fields["remember"])
+
+	return &data.Session{
+		Handle:    data.SessionHandle(sessionID),
+		UserID:    userID,
+		Provider:  fields["provider"],
+		UserAgent: fields["user_agent"],
+		IP:        fields["ip"],
+		CreatedAt: unix("created_at"),
+		LastSeen:  unix("last_seen"),
+		ExpiresAt: unix("expires_at"),
+		Remember:  remember,
+	}, nil
+}
+
+// sessionEnded returns why the session has ended by now, "expired" or "idle", or
+// "" while it's still live. Remember-me sessions never go idle.
+func (app *application) sessionEnded(s *data.Session, now time.Time) string {
+	switch {
+	case !now.Before(s.ExpiresAt):
+		return "expired"
+	case !s.Remember && app.config.Session.IdleTimeout > 0 && now.Sub(s.LastSeen) >= app.config.Session.IdleTimeout:
+		return "idle"
+	default:
+		return ""
+	}
+}
+
+// sessionActivity keeps signed-in sessions' activity up to date. A session past
+// its expiry, idle for longer than the idle timeout, or no longer tracked, is
+// signed out and the request carries on without it. Any other session has its last-seen time and IP
+// updated, which pushes its idle timeout back, and its cookie's lifetime pinned
+// to the session's, so saving it again doesn't cut a remember-me session short.
+func (app *application) sessionActivity(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("auth-session"); err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		session, err := app.sessionStore.Get(r, "auth-session")
+		if err != nil || session.IsNew {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		info, err := app.sessionInfo(r.Context(), session.ID)
+		switch {
+		case errors.Is(err, errSessionNotTracked):
+			// Every session signIn signs in is tracked, so a signed-in one that
+			// isn't has been revoked or outlived its tracking, and has ended too.
+			if authenticated, _ := session.Values["authenticated"].(bool); authenticated {
+				app.clearSession(w, r, session)
+				app.requestLogger(r).Info("session ended", "reason", "untracked")
+			}
+			next.ServeHTTP(w, r)
+			return
+		case err != nil:
+			app.logError(r, err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		now := time.Now()
+		if reason := app.sessionEnded(info, now); reason != "" {
+			app.endSession(w, r, session, info.UserID)
+			app.requestLogger(r).Info("session ended", "user_id", info.UserID, "reason", reason)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		session.Options.MaxAge = int(info.ExpiresAt.Sub(now).Seconds())
+		if now.Sub(info.LastSeen) >= sessionTouchInterval {
+			if err := app.touchSession(r, session.ID, info.ExpiresAt, now); err != nil {
+				app.logError(r, err)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// touchSession records that the session sessionID was used just now, from the
+// request's IP.
+func (app *application) touchSession(r *http.Request, sessionID string, expires, now time.Time) error {
+	ctx := r.Context()
+	key := sessionInfoKey(sessionID)
+	_, err := app.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "last_seen", now.Unix(), "ip", clientIP(r))
+		// Should the session have just been revoked, don't bring back a key that
+		// never expires.
+		pipe.ExpireAt(ctx, key, expires)
+		return nil
+	})
+	return err
+}
+
+// endSession signs the browser out of session, which belongs to userID. The
+// rest of the request sees a new, empty session in its place.
+func (app *application) endSession(w http.ResponseWriter, r *http.Request, session *sessions.Session, userID int64) {
+	if err := app.revokeSessions(r.Context(), userID, session.ID); err != nil {
+		app.logError(r, err)
+	}
+	app.clearSession(w, r, session)
+}
+
+// clearSession deletes session and has the browser drop its cookie. The rest of
+// the request sees a new, empty session in its place.
+func (app *application) clearSession(w http.ResponseWriter, r *http.Request, session *sessions.Session) {
+	session.Options.MaxAge = -1
+	if err := session.Save(r, w); err != nil {
+		app.logError(r, err)
+	}
+
+	session.ID = ""
+	session.IsNew = true
+	session.Values = make(map[any]any)
+	session.Options.MaxAge = int(app.config.Session.MaxAge.Seconds())
+}
+
+// forgetSession deletes the session sessionID, signed in or not, and stops
+// tracking it.
+func (app *application) forgetSession(ctx context.Context, sessionID string) error {
+	info, err := app.sessionInfo(ctx, sessionID)
+	switch {
+	case errors.Is(err, errSessionNotTracked):
+		return app.redisClient.Del(ctx, sessionKeyPrefix+sessionID).Err()
+	case err != nil:
+		return err
+	}
+	return app.revokeSessions(ctx, info.UserID, sessionID)
+}
+
+// userSessions lists userID's live sessions, the current one first and then the
+// most recently used. Sessions found to have ended are revoked on the way.
+func (app *application) userSessions(ctx context.Context, userID int64, currentID string) ([]data.Session, error) {
+	ids, err := app.redisClient.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	cmds := make([]*redis.StringStringMapCmd, len(ids))
+	_, err = app.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, id := range ids {
+			cmds[i] = pipe.HGetAll(ctx, sessionInfoKey(id))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var live []data.Session
+	var ended []string
+	for i, id := range ids {
+		info, err := parseSessionInfo(id, cmds[i].Val())
+		if errors.Is(err, errSessionNotTracked) || (err == nil && app.sessionEnded(info, now) != "") {
+			ended = append(ended, id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		info.Current = id == currentID
+		live = append(live, *info)
+	}
+	if err := app.revokeSessions(ctx, userID, ended...); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(live, func(i, j int) bool {
+		if live[i].Current != live[j].Current {
+			return live[i].Current
+		}
+		return live[i].LastSeen.After(live[j].LastSeen)
+	})
+	return live, nil
+}
+
+// revokeUserSession signs out userID's session with handle. It reports whether
+// there was one, and whether it was currentID.
+func (app *application) revokeUserSession(ctx context.Context, userID int64, handle, currentID string) (found, current bool, err error) {
+	ids, err := app.redisClient.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return false, false, err
+	}
+	for _, id := range ids {
+		if data.SessionHandle(id) == handle {
+			return true, id == currentID, app.revokeSessions(ctx, userID, id)
+		}
+	}
+	return false, false, nil
+}
+
+// revokeSessions signs out userID's sessions ids by deleting them, along with
+// what's tracked about them.
+func (app *application) revokeSessions(ctx context.Context, userID int64, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, 2*len(ids))
+	members := make([]any, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, sessionKeyPrefix+id, sessionInfoKey(id))
+		members = append(members, id)
+	}
+	_, err := app.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, keys...)
+		pipe.SRem(ctx, userSessionsKey(userID), members...)
+		return nil
+	})
+	return err
+}
+
+// revokeUserSessions signs userID out everywhere by deleting every session
+// recorded for them.
+func (app *application) revokeUserSessions(ctx context.Context, userID int64) error {
+	key := userSessionsKey(userID)
+	ids, err := app.redisClient.SMembers(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, 2*len(ids)+1)
+	for _, id := range ids {
+		keys = append(keys, sessionKeyPrefix+id, sessionInfoKey(id))
+	}
+	keys = append(keys, key)
+	return app.redisClient.Del(ctx, keys...).Err()
+}
+
+// dashboardSessionsHandler renders the list of the user's signed-in sessions.
+func (app *application) dashboardSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := app.sessionUser(r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.renderSessions(w, r, user.ID)
+}
+
+// dashboardRevokeSessionHandler signs out one of the user's sessions. Revoking
+// the current one signs this browser out, so it's sent back to the login page.
+func (app *application) dashboardRevokeSessionHandler(w http.ResponseWriter, r *http.Request) {
+	handle := httprouter.ParamsFromContext(r.Context()).ByName("id")
+
+	user, err := app.sessionUser(r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	session, _ := app.sessionStore.Get(r, "auth-session")
+	found, current, err := app.revokeUserSession(r.Context(), user.ID, handle, session.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if found {
+		app.requestLogger(r).Info("session revoked", "user_id", user.ID, "session", handle)
+	}
+	if current {
+		app.signedOutResponse(w, r)
+		return
+	}
+
+	app.renderSessions(w, r, user.ID)
+}
+
+// dashboardRevokeAllSessionsHandler signs the user out everywhere, or with
+// keep_current set, everywhere but here.
+func (app *application) dashboardRevokeAllSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+	keepCurrent := r.PostForm.Get("keep_current") != ""
+
+	user, err := app.sessionUser(r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if !keepCurrent {
+		if err := app.revokeUserSessions(r.Context(), user.ID); err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		app.requestLogger(r).Info("all sessions revoked", "user_id", user.ID)
+		app.signedOutResponse(w, r)
+		return
+	}
+
+	session, _ := app.sessionStore.Get(r, "auth-session")
+	ids, err := app.redisClient.SMembers(r.Context(), userSessionsKey(user.ID)).Result()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	others := slices.DeleteFunc(ids, func(id string) bool { return id == session.ID })
+	if err := app.revokeSessions(r.Context(), user.ID, others...); err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	app.requestLogger(r).Info("other sessions revoked", "user_id", user.ID, "count", len(others))
+
+	app.renderSessions(w, r, user.ID)
+}
+
+// signedOutResponse clears the session cookie of a browser whose session has
+// just been revoked and sends it to the login page.
+func (app *application) signedOutResponse(w http.ResponseWriter, r *http.Request) {
+	session, _ := app.sessionStore.Get(r, "auth-session")
+	session.Options.MaxAge = -1
+	if err := session.Save(r, w); err != nil {
+		app.logError(r, err)
+	}
+	w.Header().Set("HX-Redirect", "/")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (app *application) renderSessions(w http.ResponseWriter, r *http.Request, userID int64) {
+	session, _ := app.sessionStore.Get(r, "auth-session")
+	list, err := app.userSessions(r.Context(), userID, session.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = templates.Sessions(list).Render(r.Context(), w)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/layer8s/home-dashboard-app/internal/data"
+)
+
+func TestSessionEnded(t *testing.T) {
+	app, _, _ := newTestApplication(t)
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		session     data.Session
+		idleTimeout time.Duration
+		want        string
+	}{
+		{"live", data.Session{LastSeen: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}, 2 * time.Hour, ""},
+		{"expired", data.Session{LastSeen: now, ExpiresAt: now}, 2 * time.Hour, "expired"},
+		{"idle", data.Session{LastSeen: now.Add(-2 * time.Hour), ExpiresAt: now.Add(time.Hour)}, 2 * time.Hour, "idle"},
+		{"remembered sessions don't idle", data.Session{LastSeen: now.Add(-48 * time.Hour), ExpiresAt: now.Add(time.Hour), Remember: true}, 2 * time.Hour, ""},
+		{"remembered sessions expire", data.Session{LastSeen: now, ExpiresAt: now.Add(-time.Second), Remember: true}, 2 * time.Hour, "expired"},
+		{"idle timeout disabled", data.Session{LastSeen: now.Add(-48 * time.Hour), ExpiresAt: now.Add(time.Hour)}, 0, ""},
+	}
+	for _, tt := range tests {
+		app.config.Session.IdleTimeout = tt.idleTimeout
+		if got := app.sessionEnded(&tt.session, now); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSessionActivity(t *testing.T) {
+	tests := []struct {
+		name     string
+		lastSeen time.Duration
+		remember bool
+		untrack  bool
+		revoked  bool
+	}{
+		{"active", -time.Minute, false, false, false},
+		{"idle", -3 * time.Hour, false, false, true},
+		{"remembered", -3 * time.Hour, true, false, false},
+		{"untracked", -time.Minute, false, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, mr, _ := newTestApplication(t)
+			app.config.Session.IdleTimeout = 2 * time.Hour
+			const userID = 3
+
+			id, w := signedInSession(t, app, userID, tt.remember)
+			mr.HSet(sessionInfoKey(id), "last_seen", formatUnix(time.Now().Add(tt.lastSeen)))
+			if tt.untrack {
+				mr.Del(sessionInfoKey(id))
+			}
+
+			var authenticated bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				session, _ := app.sessionStore.Get(r, "auth-session")
+				authenticated, _ = session.Values["authenticated"].(bool)
+			})
+			r := withCookies(httptest.NewRequest(http.MethodGet, "/v1/dashboard", nil), w)
+			app.sessionActivity(next).ServeHTTP(httptest.NewRecorder(), r)
+
+			if authenticated == tt.revoked {
+				t.Errorf("authenticated = %v, want %v", authenticated, !tt.revoked)
+			}
+			if exists := mr.Exists(sessionKeyPrefix + id); exists == tt.revoked {
+				t.Errorf("session stored = %v, want %v", exists, !tt.revoked)
+			}
+			if !tt.revoked {
+				lastSeen := mr.HGet(sessionInfoKey(id), "last_seen")
+				if lastSeen != formatUnix(time.Now()) && lastSeen != formatUnix(time.Now().Add(-time.Second)) {
+					t.Errorf("last seen %s wasn't brought up to date", lastSeen)
+				}
+			}
+		})
+	}
+}
+
+func TestRevokeUserSessions(t *testing.T) {
+	app, mr, _ := newTestApplication(t)
+
+	first, _ := signedInSession(t, app, 1, false)
+	second, _ := signedInSession(t, app, 1, true)
+	other, _ := signedInSession(t, app, 2, false)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if err := app.revokeUserSessions(r.Context(), 1); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{first, second} {
+		if mr.Exists(sessionKeyPrefix+id) || mr.Exists(sessionInfoKey(id)) {
+			t.Errorf("session %s wasn't revoked", id)
+		}
+	}
+	if !mr.Exists(sessionKeyPrefix+other) || !mr.Exists(sessionInfoKey(other)) {
+		t.Error("another user's session was revoked")
+	}
+}
+
+func formatUnix(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"database/sql"
+	"fmt"
 	"net/http"
 )
 
@@ -12,12 +13,27 @@ func (app *application) showTeamHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	app.logger.Info("attempting to fetch team", "id", id)
+	format, err := app.negotiateFormat(r)
+	if err != nil {
+		app.notAcceptableResponse(w, r)
+		return
+	}
+
+	rev, err := app.queries.GetArchiveRevision(r.Context())
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if app.revalidateSeason(w, r, rev, format) {
+		return
+	}
+
+	app.requestLogger(r).Info("attempting to fetch team", "id", id)
 
 	// Use the SQLC-generated query method
 	team, err := app.queries.GetTeamById(r.Context(), int32(id))
 	if err != nil {
-		app.logger.Error("database error", "error", err)
+		app.requestLogger(r).Error("database error", "error", err)
 		if err == sql.ErrNoRows {
 			app.notFoundResponse(w, r)
 			return
@@ -26,7 +42,11 @@ func (app *application) showTeamHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, envelope{"team": team}, nil)
+	if app.checkNotModified(w, r, rev, format, team.Year) {
+		return
+	}
+
+	err = app.writeFormatted(w, http.StatusOK, format, "team", fmt.Sprintf("team-%d", id), team, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
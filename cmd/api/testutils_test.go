@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/layer8s/home-dashboard-app/internal/config"
+	"github.com/layer8s/home-dashboard-app/internal/data"
+	"github.com/layer8s/home-dashboard-app/internal/db"
+	"github.com/rbcervilla/redisstore/v8"
+)
+
+// newTestApplication returns an application backed by an in-memory Redis and a
+// mocked database, which the test sets its expectations on.
+func newTestApplication(t *testing.T) (*application, *miniredis.Miniredis, sqlmock.Sqlmock) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+
+	store, err := redisstore.NewRedisStore(context.Background(), redisClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.KeyPrefix(sessionKeyPrefix)
+
+	dbConn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		dbConn.Close()
+	})
+
+	cfg := config.Defaults("development")
+	queries := db.New(dbConn)
+	app := &application{
+		config:       &cfg,
+		logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		db:           dbConn,
+		queries:      queries,
+		tokens:       data.NewTokenService(queries),
+		sessionStore: store,
+		redisClient:  redisClient,
+		metrics:      newMetrics(nil, nil, nil),
+	}
+	return app, mr, mock
+}
+
+// withCookies returns r with the cookies set by the response recorded in w, as a
+// browser would send them with its next request.
+func withCookies(r *http.Request, w *httptest.ResponseRecorder) *http.Request {
+	for _, cookie := range w.Result().Cookies() {
+		r.AddCookie(cookie)
+	}
+	return r
+}
+
+// signedInSession saves a session signed in as userID, tracked as signIn tracks
+// it, and returns its ID and the response that set its cookie.
+func signedInSession(t *testing.T, app *application, userID int64, remember bool) (string, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/login", nil)
+	w := httptest.NewRecorder()
+	session, _ := app.sessionStore.Get(r, "auth-session")
+	session.Values["authenticated"] = true
+	session.Values["provider"] = config.PasswordProvider
+	if err := session.Save(r, w); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.trackSession(r, userID, session.ID, config.PasswordProvider, remember); err != nil {
+		t.Fatal(err)
+	}
+	return session.ID, w
+}
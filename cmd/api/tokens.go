@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/layer8s/home-dashboard-app/internal/data"
+	"github.com/layer8s/home-dashboard-app/internal/validator"
+	"github.com/layer8s/home-dashboard-app/templates"
+)
+
+// passwordResetTokenTTL is how long a password reset token can be used for.
+const passwordResetTokenTTL = 45 * time.Minute
+
+// apiTokenInput is what's asked for when creating a personal API token, through
+// the JSON API or the dashboard form.
+type apiTokenInput struct {
+	Name          string `json:"name"`
+	ExpiresInDays int    `json:"expires_in_days"`
+	ReadOnly      bool   `json:"read_only"`
+}
+
+// createAPIToken validates input and creates the token for userID. It returns nil
+// and no error if validation failed; the problems are left in v.
+func (app *application) createAPIToken(ctx context.Context, userID int64, input apiTokenInput, v *validator.Validator) (*data.APIToken, error) {
+	if data.ValidateAPIToken(v, input.Name, input.ExpiresInDays); !v.Valid() {
+		return nil, nil
+	}
+
+	scope := data.ScopeAPI
+	if input.ReadOnly {
+		scope = data.ScopeAPIReadOnly
+	}
+	ttl := time.Duration(input.ExpiresInDays) * 24 * time.Hour
+
+	token, err := app.tokens.NewAPIToken(ctx, userID, input.Name, ttl, scope)
+	if err != nil {
+		return nil, err
+	}
+	app.loggerFromContext(ctx).Info("api token created", "user_id", userID, "token_id", token.ID, "scope", scope)
+	return token, nil
+}
+
+func (app *application) listTokensHandler(w http.ResponseWriter, r *http.Request) {
+	user := contextGetUser(r)
+
+	tokens, err := app.tokens.ListAPITokens(r.Context(), user.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"tokens": tokens}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// createTokenHandler creates a personal API token. Its plaintext is only ever
+// returned in this response.
+func (app *application) createTokenHandler(w http.ResponseWriter, r *http.Request) {
+	user := contextGetUser(r)
+
+	var input apiTokenInput
+	if err := app.readJSON(w, r, &input); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	token, err := app.createAPIToken(r.Context(), user.ID, input, v)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"token": token}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *application) deleteTokenHandler(w http.ResponseWriter, r *http.Request) {
+	user := contextGetUser(r)
+
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.tokens.RevokeAPIToken(r.Context(), user.ID, id)
+	switch {
+	case errors.Is(err, data.ErrTokenNotFound):
+		app.notFoundResponse(w, r)
+		return
+	case err != nil:
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	app.requestLogger(r).Info("api token revoked", "user_id", user.ID, "token_id", id)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "token revoked"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// dashboardCreateTokenHandler creates a token from the dashboard form and
+// re-renders the token list with the new token's plaintext shown once.
+func (app *application) dashboardCreateTokenHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	user, err := app.sessionUser(r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	input := apiTokenInput{
+		Name:     r.PostForm.Get("name"),
+		ReadOnly: r.PostForm.Get("read_only") != "",
+	}
+	if days := r.PostForm.Get("expires_in_days"); days != "" {
+		if input.ExpiresInDays, err = strconv.Atoi(days); err != nil {
+			v.AddError("expires_in_days", "must be an integer value")
+		}
+	}
+
+	var created *data.APIToken
+	if v.Valid() {
+		created, err = app.createAPIToken(r.Context(), user.ID, input, v)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	app.renderAPITokens(w, r, user.ID, created, v.Errors)
+}
+
+// dashboardRevokeTokenHandler revokes a token from the dashboard and re-renders
+// the token list.
+func (app *application) dashboardRevokeTokenHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user, err := app.sessionUser(r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.tokens.RevokeAPIToken(r.Context(), user.ID, id)
+	if err != nil && !errors.Is(err, data.ErrTokenNotFound) {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	app.requestLogger(r).Info("api token revoked", "user_id", user.ID, "token_id", id)
+
+	app.renderAPITokens(w, r, user.ID, nil, nil)
+}
+
+func (app *application) renderAPITokens(w http.ResponseWriter, r *http.Request, userID int64, created *data.APIToken, errs map[string]string) {
+	tokens, err := app.tokens.ListAPITokens(r.Context(), userID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = templates.APITokens(tokens, created, errs).Render(r.Context(), w)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// createPasswordResetTokenHandler emails a password reset token to the address
+// given if it belongs to an activated password account. The response is the same
+// either way, and the lookup happens after it's sent, so neither the answer nor
+// how long it takes reveals whether the address is registered.
+func (app *application) createPasswordResetTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Email string `json:"email"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	input.Email = normalizeEmail(input.Email)
+
+	v := validator.New()
+	if data.ValidateEmail(v, input.Email); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	app.background(r.Context(), func(ctx context.Context) {
+		logger := app.loggerFromContext(ctx)
+
+		user, err := app.queries.GetUserByEmail(ctx, nullableString(input.Email))
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return
+		case err != nil:
+			logger.Error("looking up user for password reset failed", "error", err)
+			return
+		case !user.Activated:
+			return
+		}
+
+		token, err := app.tokens.New(ctx, user.ID, passwordResetTokenTTL, data.ScopePasswordReset)
+		if err != nil {
+			logger.Error("creating password reset token failed", "user_id", user.ID, "error", err)
+			return
+		}
+
+		data := map[string]any{
+			"passwordResetToken": token.Plaintext,
+			"expiresInMinutes":   int(passwordResetTokenTTL.Minutes()),
+		}
+		err = app.mailer.Send(ctx, user.Email.String, "password_reset.tmpl", data)
+		if err != nil {
+			logger.Error("sending password reset email failed", "user_id", user.ID, "error", err)
+		}
+	})
+
+	env := envelope{"message": "if an activated account uses that email address, password reset instructions have been sent to it"}
+	err = app.writeJSON(w, http.StatusAccepted, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -1,12 +1,25 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/layer8s/home-dashboard-app/internal/config"
 	"github.com/layer8s/home-dashboard-app/internal/data"
+	"github.com/layer8s/home-dashboard-app/internal/db"
 	"github.com/layer8s/home-dashboard-app/internal/validator"
+	"github.com/layer8s/home-dashboard-app/templates"
+	"github.com/lib/pq"
 )
 
+// activationTokenTTL is how long a new user has to activate their account, as
+// promised by the welcome email.
+const activationTokenTTL = 3 * 24 * time.Hour
+
 func (app *application) registerUserHandler(w http.ResponseWriter, r *http.Request) {
 	// Create an anonymous struct to hold the expected data from the request body.
 	var input struct {
@@ -15,10 +28,6 @@ func (app *application) registerUserHandler(w http.ResponseWriter, r *http.Reque
 		Password *string `json:"password"`
 	}
 
-	// var (
-	// 	ErrDuplicateEmail = errors.New("duplicate email")
-	// )
-
 	// Parse the request body into the anonymous struct.
 	err := app.readJSON(w, r, &input)
 	if err != nil {
@@ -26,16 +35,40 @@ func (app *application) registerUserHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	v := validator.New()
+	if input.Name == nil {
+		v.AddError("name", "must be provided")
+	}
+	if input.Email == nil {
+		v.AddError("email", "must be provided")
+	}
+	if input.Password == nil {
+		v.AddError("password", "must be provided")
+	}
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
 	// Copy the data from the request body into a new User struct. Notice also that we
 	// set the Activated field to false, which isn't strictly necessary because the
 	// Activated field will have the zero-value of false by default. But setting this
 	// explicitly helps to make our intentions clear to anyone reading the code.
 	user := &data.User{
 		Name:      *input.Name,
-		Email:     *input.Email,
+		Email:     normalizeEmail(*input.Email),
 		Activated: false,
 	}
 
+	// Validate the input before hashing the password, which is deliberately slow.
+	data.ValidateName(v, user.Name)
+	data.ValidateEmail(v, user.Email)
+	data.ValidatePasswordPlaintext(v, *input.Password)
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
 	// Use the Password.Set() method to generate and store the hashed and plaintext
 	// passwords.
 	err = user.Password.Set(*input.Password)
@@ -44,62 +77,379 @@ func (app *application) registerUserHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	token, err := app.insertPasswordUser(r.Context(), user)
+	if err != nil {
+		if errors.Is(err, data.ErrDuplicateEmail) {
+			v.AddError("email", "a user with this email address already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	app.requestLogger(r).Info("user registered", "id", user.ID, "provider", config.PasswordProvider)
+
+	app.background(r.Context(), func(ctx context.Context) {
+		data := map[string]any{
+			"activationToken": token.Plaintext,
+			"userID":          user.ID,
+		}
+
+		err := app.mailer.Send(ctx, user.Email, "user_welcome.tmpl", data)
+		if err != nil {
+			app.loggerFromContext(ctx).Error("sending welcome email failed", "user_id", user.ID, "error", err)
+		}
+	})
+
+	// Write a JSON response containing the user data along with a 202 Accepted status
+	// code, as the welcome email is still on its way.
+	err = app.writeJSON(w, http.StatusAccepted, envelope{"user": user}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// insertPasswordUser stores a new user who signs in with a password, along with
+// their password identity and an activation token, and fills in user's ID and
+// creation time. It returns data.ErrDuplicateEmail if the address already has a
+// password account.
+func (app *application) insertPasswordUser(ctx context.Context, user *data.User) (*data.Token, error) {
+	tx, err := app.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := app.txQueries(tx)
+	row, err := qtx.InsertUser(ctx, db.InsertUserParams{
+		Name:         user.Name,
+		Email:        nullableString(user.Email),
+		PasswordHash: []byte(*user.Password.Hash()),
+		Activated:    user.Activated,
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Constraint == "users_password_email_idx" {
+			return nil, data.ErrDuplicateEmail
+		}
+		return nil, err
+	}
+	user.ID = row.ID
+	user.CreatedAt = row.CreatedAt
+	user.Version = int(row.Version)
+
+	_, err = qtx.CreateUserIdentity(ctx, db.CreateUserIdentityParams{
+		UserID:     user.ID,
+		Provider:   config.PasswordProvider,
+		ProviderID: strconv.FormatInt(user.ID, 10),
+		Email:      nullableString(user.Email),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := data.NewTokenService(qtx).New(ctx, user.ID, activationTokenTTL, data.ScopeActivation)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, tx.Commit()
+}
+
+// activateUserHandler consumes the activation token from the welcome email. It
+// also marks the user's email address as verified, which lets them link
+// identity providers that report the same address.
+func (app *application) activateUserHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		TokenPlaintext string `json:"token"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
 	v := validator.New()
+	if data.ValidateTokenPlaintext(v, input.TokenPlaintext); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	tokenUser, err := app.tokens.GetUserForToken(r.Context(), data.ScopeActivation, input.TokenPlaintext)
+	switch {
+	case errors.Is(err, data.ErrTokenNotFound):
+		v.AddError("token", "invalid or expired activation token")
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	case err != nil:
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	user, err := app.activateUser(r.Context(), tokenUser.UserID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	app.requestLogger(r).Info("user activated", "id", user.ID)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"user": user}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// activateUser activates userID and deletes their activation tokens, so each can
+// only be used once.
+func (app *application) activateUser(ctx context.Context, userID int64) (*data.User, error) {
+	tx, err := app.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
-	data.ValidateUser(v, user)
+	qtx := app.txQueries(tx)
+	row, err := qtx.ActivateUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = qtx.VerifyIdentityEmail(ctx, db.VerifyIdentityEmailParams{
+		UserID:   userID,
+		Provider: config.PasswordProvider,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = data.NewTokenService(qtx).DeleteAllForUser(ctx, data.ScopeActivation, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &data.User{
+		ID:        row.ID,
+		CreatedAt: row.CreatedAt,
+		Name:      row.Name,
+		Email:     row.Email.String,
+		Activated: row.Activated,
+		Version:   int(row.Version),
+	}, nil
+}
+
+// updateUserPasswordHandler sets a new password with a token from the password
+// reset email. The user's other reset tokens stop working, and they're signed
+// out of every session, since whoever knew the old password may hold one.
+func (app *application) updateUserPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Password       string `json:"password"`
+		TokenPlaintext string `json:"token"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	data.ValidatePasswordPlaintext(v, input.Password)
+	data.ValidateTokenPlaintext(v, input.TokenPlaintext)
 	if !v.Valid() {
 		app.failedValidationResponse(w, r, v.Errors)
 		return
 	}
 
-	// userParams := &db.InsertUserParams{
-	// 	Name:         *input.Name,
-	// 	Email:        *input.Email,
-	// 	Activated:    false,
-	// 	PasswordHash: []byte(*user.Password.Hash()),
-	// }
+	tokenUser, err := app.tokens.GetUserForToken(r.Context(), data.ScopePasswordReset, input.TokenPlaintext)
+	switch {
+	case errors.Is(err, data.ErrTokenNotFound):
+		v.AddError("token", "invalid or expired password reset token")
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	case err != nil:
+		app.serverErrorResponse(w, r, err)
+		return
+	}
 
-	// // // Insert the user data into the database.
-	// userRow, err := app.queries.InsertUser(r.Context(), *userParams)
-	// if err != nil {
-	// 	if err.Error() == `pq: duplicate key value violates unique constraint "users_email_key"` {
-	// 		app.serverErrorResponse(w, r, ErrDuplicateEmail)
-	// 		return
-	// 	}
-	// 	app.serverErrorResponse(w, r, err)
-	// 	return
-	// }
+	var user data.User
+	if err := user.Password.Set(input.Password); err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
 
-	// user.ID = userRow.ID
-	// user.CreatedAt = userRow.CreatedAt
+	err = app.resetPassword(r.Context(), tokenUser.UserID, *user.Password.Hash())
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if err := app.revokeUserSessions(r.Context(), tokenUser.UserID); err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	app.requestLogger(r).Info("password reset", "user_id", tokenUser.UserID)
 
-	// tokenService := data.NewTokenService(app.queries)
+	env := envelope{"message": "your password was successfully reset"}
+	err = app.writeJSON(w, http.StatusOK, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
 
-	// token, err := tokenService.New(r.Context(), user.ID, 3*24*time.Hour, data.ScopeActivation)
-	// if err != nil {
-	// 	app.serverErrorResponse(w, r, err)
-	// 	return
-	// }
+// resetPassword stores userID's new password hash and deletes all their
+// password reset tokens.
+func (app *application) resetPassword(ctx context.Context, userID int64, hash string) error {
+	tx, err := app.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	// app.background(func() {
+	qtx := app.txQueries(tx)
+	err = qtx.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{
+		PasswordHash: []byte(hash),
+		ID:           userID,
+	})
+	if err != nil {
+		return err
+	}
 
-	// 	data := map[string]any{
-	// 		"activationToken": token.Plaintext,
-	// 		"userID":          user.ID,
-	// 	}
-	// 	app.logger.Info("User data info: ", data)
+	err = data.NewTokenService(qtx).DeleteAllForUser(ctx, data.ScopePasswordReset, userID)
+	if err != nil {
+		return err
+	}
 
-	// 	err = app.mailer.Send(user.Email, "user_welcome.tmpl", data)
-	// 	if err != nil {
-	// 		app.logger.Error((err.Error()))
-	// 	}
+	return tx.Commit()
+}
 
-	// })
+// passwordLoginHandler signs in a user with their email address and password,
+// creating the same session as signing in with an identity provider. It takes
+// the login page's form, answered with a redirect to the dashboard, or a JSON
+// body, answered with the user.
+func (app *application) passwordLoginHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+		Remember bool   `json:"remember"`
+	}
 
-	// Write a JSON response containing the user data along with a 201 Created status
-	// code.
-	err = app.writeJSON(w, http.StatusAccepted, envelope{"user": user}, nil)
+	jsonRequest := isJSONRequest(r)
+	if jsonRequest {
+		if err := app.readJSON(w, r, &input); err != nil {
+			app.badRequestResponse(w, r, err)
+			return
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			app.badRequestResponse(w, r, err)
+			return
+		}
+		input.Email = r.PostForm.Get("email")
+		input.Password = r.PostForm.Get("password")
+		input.Remember = r.PostForm.Get("remember") != ""
+	}
+
+	input.Email = normalizeEmail(input.Email)
+
+	v := validator.New()
+	data.ValidateEmail(v, input.Email)
+	data.ValidatePasswordPlaintext(v, input.Password)
+	if !v.Valid() {
+		if jsonRequest {
+			app.failedValidationResponse(w, r, v.Errors)
+		} else {
+			app.renderLoginError(w, r, http.StatusUnprocessableEntity, "Enter your email address and a password of 8 to 72 characters.")
+		}
+		return
+	}
+
+	row, err := app.queries.GetUserByEmail(r.Context(), nullableString(input.Email))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	user := &data.User{
+		ID:        row.ID,
+		CreatedAt: row.CreatedAt,
+		Name:      row.Name,
+		Email:     row.Email.String,
+		Activated: row.Activated,
+		Version:   int(row.Version),
+	}
+	found := err == nil
+	if found {
+		user.Password.SetHash(string(row.PasswordHash))
+	} else if err := user.Password.SetDummyHash(); err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	match, err := user.Password.Matches(input.Password)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
+		return
+	}
+	match = match && found
+
+	switch {
+	case !match:
+		if jsonRequest {
+			app.invalidCredentialsResponse(w, r)
+		} else {
+			app.renderLoginError(w, r, http.StatusUnauthorized, "Incorrect email address or password.")
+		}
+		return
+	case !user.Activated:
+		if jsonRequest {
+			app.inactiveAccountResponse(w, r)
+		} else {
+			app.renderLoginError(w, r, http.StatusForbidden, "Activate your account with the link in your welcome email before signing in.")
+		}
+		return
+	}
+
+	identity := userIdentity{
+		Subject:       strconv.FormatInt(user.ID, 10),
+		Email:         user.Email,
+		Name:          user.Name,
+		EmailVerified: true,
+	}
+	_, err = app.queries.RecordIdentityLogin(r.Context(), db.RecordIdentityLoginParams{
+		Provider:      config.PasswordProvider,
+		ProviderID:    identity.Subject,
+		Email:         nullableString(identity.Email),
+		EmailVerified: identity.EmailVerified,
+	})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if err := app.signIn(w, r, user.ID, config.PasswordProvider, identity, input.Remember); err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if !jsonRequest {
+		http.Redirect(w, r, "/v1/dashboard", http.StatusSeeOther)
+		return
+	}
+	err = app.writeJSON(w, http.StatusOK, envelope{"user": user}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// renderLoginError shows the login page again with message above the password
+// form.
+func (app *application) renderLoginError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	err := templates.Login(app.authManager.Available(), message, app.config.Session.RememberMeMaxAge > 0).Render(r.Context(), w)
+	if err != nil {
+		app.logError(r, err)
 	}
 }
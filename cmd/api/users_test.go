@@ -0,0 +1,121 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/layer8s/home-dashboard-app/internal/data"
+)
+
+func TestUpdateUserPassword(t *testing.T) {
+	app, mr, mock := newTestApplication(t)
+	const userID = 5
+	token := strings.Repeat("A", 26)
+
+	first, _ := signedInSession(t, app, userID, false)
+	second, _ := signedInSession(t, app, userID, true)
+	other, _ := signedInSession(t, app, userID+1, false)
+
+	mock.ExpectQuery("-- name: GetUserForToken").
+		WithArgs(data.HashToken(token), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "token_id", "scope"}).AddRow(userID, "Alice", 9, data.ScopePasswordReset))
+	mock.ExpectBegin()
+	mock.ExpectExec("-- name: UpdateUserPassword").
+		WithArgs(sqlmock.AnyArg(), userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("-- name: DeleteToken").
+		WithArgs(data.ScopePasswordReset, userID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	body := `{"password": "correct horse battery staple", "token": "` + token + `"}`
+	r := httptest.NewRequest(http.MethodPut, "/v1/users/password", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	app.updateUserPasswordHandler(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	for _, id := range []string{first, second} {
+		if mr.Exists(sessionKeyPrefix+id) || mr.Exists(sessionInfoKey(id)) {
+			t.Errorf("session %s survived the password reset", id)
+		}
+	}
+	if mr.Exists(userSessionsKey(userID)) {
+		t.Error("the user's session list survived the password reset")
+	}
+	if !mr.Exists(sessionKeyPrefix + other) {
+		t.Error("another user's session was revoked")
+	}
+}
+
+func TestUpdateUserPasswordUnknownToken(t *testing.T) {
+	app, _, mock := newTestApplication(t)
+	token := strings.Repeat("B", 26)
+
+	mock.ExpectQuery("-- name: GetUserForToken").
+		WithArgs(data.HashToken(token), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "token_id", "scope"}))
+
+	body := `{"password": "correct horse battery staple", "token": "` + token + `"}`
+	r := httptest.NewRequest(http.MethodPut, "/v1/users/password", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	app.updateUserPasswordHandler(w, r)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestRegisterUserValidation(t *testing.T) {
+	app, _, _ := newTestApplication(t)
+
+	tests := []struct {
+		body string
+		want []string
+	}{
+		{`{}`, []string{"name", "email", "password"}},
+		{`{"name": "Alice", "email": "alice@example.com"}`, []string{"password"}},
+		{`{"name": "", "email": "alice", "password": "short"}`, []string{"name", "email", "password"}},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/v1/users", strings.NewReader(tt.body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.registerUserHandler(w, r)
+
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: status %d, want %d", tt.body, w.Code, http.StatusUnprocessableEntity)
+			continue
+		}
+		for _, field := range tt.want {
+			if !strings.Contains(w.Body.String(), `"`+field+`"`) {
+				t.Errorf("%s: response %s has no error for %s", tt.body, w.Body, field)
+			}
+		}
+	}
+}
+
+func TestPasswordLoginNormalizesEmail(t *testing.T) {
+	app, _, mock := newTestApplication(t)
+
+	mock.ExpectQuery("-- name: GetUserByEmail").
+		WithArgs("alice@example.com").
+		WillReturnError(sql.ErrNoRows)
+
+	body := `{"email": "  Alice@Example.COM ", "password": "correct horse battery staple"}`
+	r := httptest.NewRequest(http.MethodPost, "/v1/login", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	app.passwordLoginHandler(w, r)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
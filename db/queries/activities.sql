@@ -0,0 +1,5 @@
+-- name: ListActivitiesByTeamIDs :many
+SELECT "id", "date", "team_id", "player_id", "bidAmount", "action"
+FROM "activities"
+WHERE "team_id" = ANY(@team_ids::int[])
+ORDER BY "date";
@@ -0,0 +1,4 @@
+-- name: GetArchiveRevision :one
+SELECT id, revision, current_season, updated_at, last_import_seconds
+FROM archive_revision
+WHERE id = true;
@@ -0,0 +1,5 @@
+-- name: ListDraftsByTeamIDs :many
+SELECT "id", "team_id", "player_id", "overallPick", "roundNum", "roundPick", "keeperStatus", "bidAmount", "nominating_team_id"
+FROM "drafts"
+WHERE "team_id" = ANY(@team_ids::int[])
+ORDER BY "overallPick";
@@ -0,0 +1,40 @@
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (
+    user_id,
+    provider,
+    provider_id,
+    email,
+    email_verified
+)
+VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, user_id, provider, provider_id, email, email_verified, created_at, last_login_at;
+
+-- name: RecordIdentityLogin :one
+UPDATE user_identities
+SET
+    email = $3,
+    email_verified = $4,
+    last_login_at = NOW()
+WHERE provider = $1 AND provider_id = $2
+RETURNING user_id;
+
+-- name: FindUserByVerifiedEmail :one
+SELECT users.id, users.name
+FROM users
+JOIN user_identities ON user_identities.user_id = users.id
+WHERE lower(user_identities.email) = lower($1) AND user_identities.email_verified
+ORDER BY users.id
+LIMIT 1;
+
+-- name: ListUserIdentities :many
+SELECT id, user_id, provider, provider_id, email, email_verified, created_at, last_login_at
+FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at, id;
+
+-- name: VerifyIdentityEmail :exec
+UPDATE user_identities
+SET email_verified = true
+WHERE user_id = $1 AND provider = $2;
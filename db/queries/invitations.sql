@@ -0,0 +1,42 @@
+-- name: InsertLeagueInvitation :one
+INSERT INTO league_invitations (hash, league_id, email, role, owner, team_id, invited_by, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, hash, league_id, email, role, owner, team_id, invited_by, created_at, expires_at, accepted_at, accepted_by, revoked_at;
+
+-- name: ListLeagueInvitations :many
+SELECT id, hash, league_id, email, role, owner, team_id, invited_by, created_at, expires_at, accepted_at, accepted_by, revoked_at
+FROM league_invitations
+WHERE league_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: RevokeLeagueInvitation :execrows
+UPDATE league_invitations
+SET revoked_at = NOW()
+WHERE id = $1 AND league_id = $2 AND accepted_at IS NULL AND revoked_at IS NULL;
+
+-- name: AcceptLeagueInvitation :one
+UPDATE league_invitations
+SET accepted_at = NOW(), accepted_by = @user_id
+WHERE hash = @hash AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()
+    AND lower(email) IN (
+        SELECT lower(user_identities.email)
+        FROM user_identities
+        WHERE user_identities.user_id = @user_id AND user_identities.email_verified
+    )
+RETURNING id, hash, league_id, email, role, owner, team_id, invited_by, created_at, expires_at, accepted_at, accepted_by, revoked_at;
+
+-- name: AcceptLeagueInvitationsByEmail :many
+UPDATE league_invitations
+SET accepted_at = NOW(), accepted_by = @user_id
+WHERE accepted_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()
+    AND lower(email) IN (
+        SELECT lower(user_identities.email)
+        FROM user_identities
+        WHERE user_identities.user_id = @user_id AND user_identities.email_verified
+    )
+RETURNING id, hash, league_id, email, role, owner, team_id, invited_by, created_at, expires_at, accepted_at, accepted_by, revoked_at;
+
+-- name: VoidLeagueInvitation :exec
+UPDATE league_invitations
+SET accepted_at = NULL, accepted_by = NULL, revoked_at = NOW()
+WHERE id = $1;
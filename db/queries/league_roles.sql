@@ -0,0 +1,29 @@
+-- name: GetLeagueRole :one
+SELECT role
+FROM league_roles
+WHERE user_id = $1 AND league_id = $2;
+
+-- name: ListLeagueRoles :many
+SELECT league_roles.user_id, users.name, users.email, league_roles.role, league_roles.granted_by, league_roles.created_at, league_roles.updated_at
+FROM league_roles
+JOIN users ON users.id = league_roles.user_id
+WHERE league_roles.league_id = $1
+ORDER BY league_roles.created_at, league_roles.user_id;
+
+-- name: UpsertLeagueRole :one
+INSERT INTO league_roles (user_id, league_id, role, granted_by)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, league_id) DO UPDATE
+SET role = EXCLUDED.role, granted_by = EXCLUDED.granted_by, updated_at = NOW()
+RETURNING user_id, league_id, role, granted_by, created_at, updated_at;
+
+-- name: DeleteLeagueRole :execrows
+DELETE FROM league_roles
+WHERE user_id = $1 AND league_id = $2;
+
+-- name: IsSiteAdmin :one
+SELECT EXISTS (
+    SELECT 1
+    FROM user_identities
+    WHERE user_id = @user_id AND email_verified AND email = ANY(@emails::citext[])
+);
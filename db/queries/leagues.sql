@@ -91,3 +91,8 @@ OFFSET $8;
 
 
 
+
+-- name: ListLeaguesByIDs :many
+SELECT "id", "leagueId", "year", "teamCount", "currentWeek", "nflWeek"
+FROM "leagues"
+WHERE "id" = ANY(@ids::int[]);
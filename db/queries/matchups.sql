@@ -0,0 +1,5 @@
+-- name: ListMatchupsByTeamIDs :many
+SELECT "id", "week", "home_team_id", "away_team_id", "homeScore", "awayScore", "isPlayoff", "matchupType"
+FROM "matchups"
+WHERE "home_team_id" = ANY(@team_ids::int[]) OR "away_team_id" = ANY(@team_ids::int[])
+ORDER BY "week";
@@ -0,0 +1,4 @@
+-- name: ListPlayersByIDs :many
+SELECT "id", "espnId", "name", "position"
+FROM "players"
+WHERE "id" = ANY(@ids::int[]);
@@ -0,0 +1,90 @@
+-- name: InsertTeamClaim :one
+INSERT INTO team_claims (user_id, league_id, owner, team_id, message)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, league_id, owner, team_id, status, message, decided_by, created_at, updated_at;
+
+-- name: InsertTeamClaimEvent :exec
+INSERT INTO team_claim_events (claim_id, status, actor_id, note)
+VALUES ($1, $2, $3, $4);
+
+-- name: GetTeamClaim :one
+SELECT id, user_id, league_id, owner, team_id, status, message, decided_by, created_at, updated_at
+FROM team_claims
+WHERE id = $1;
+
+-- name: UpdateTeamClaimStatus :one
+UPDATE team_claims
+SET status = @status, decided_by = @decided_by, updated_at = NOW()
+WHERE id = @id AND status = ANY(@from_statuses::text[])
+RETURNING id, user_id, league_id, owner, team_id, status, message, decided_by, created_at, updated_at;
+
+-- name: ListUserTeamClaims :many
+SELECT id, user_id, league_id, owner, team_id, status, message, decided_by, created_at, updated_at
+FROM team_claims
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: ListLeagueTeamClaims :many
+SELECT team_claims.id, team_claims.user_id, users.name AS user_name, team_claims.league_id, team_claims.owner, team_claims.team_id, team_claims.status, team_claims.message, team_claims.decided_by, team_claims.created_at, team_claims.updated_at
+FROM team_claims
+JOIN users ON users.id = team_claims.user_id
+WHERE team_claims.league_id = @league_id AND (@status::text = '' OR team_claims.status = @status)
+ORDER BY team_claims.created_at DESC, team_claims.id DESC;
+
+-- name: ListReviewableTeamClaims :many
+SELECT team_claims.id, team_claims.user_id, users.name AS user_name, team_claims.league_id, team_claims.owner, team_claims.team_id, team_claims.status, team_claims.message, team_claims.decided_by, team_claims.created_at, team_claims.updated_at
+FROM team_claims
+JOIN users ON users.id = team_claims.user_id
+WHERE team_claims.status = 'pending'
+    AND team_claims.user_id <> @user_id
+    AND (@all_leagues::bool OR team_claims.league_id IN (
+        SELECT league_roles.league_id
+        FROM league_roles
+        WHERE league_roles.user_id = @user_id AND league_roles.role IN ('admin', 'commissioner')
+    ))
+ORDER BY team_claims.created_at, team_claims.id;
+
+-- name: ListTeamClaimEvents :many
+SELECT team_claim_events.id, team_claim_events.status, team_claim_events.actor_id, users.name AS actor_name, team_claim_events.note, team_claim_events.created_at
+FROM team_claim_events
+LEFT JOIN users ON users.id = team_claim_events.actor_id
+WHERE team_claim_events.claim_id = $1
+ORDER BY team_claim_events.created_at, team_claim_events.id;
+
+-- name: HasApprovedTeamClaim :one
+SELECT EXISTS (
+    SELECT 1
+    FROM team_claims
+    WHERE user_id = $1 AND league_id = $2 AND status = 'approved'
+);
+
+-- name: TeamClaimTargetExists :one
+SELECT EXISTS (
+    SELECT 1
+    FROM teams
+    JOIN leagues ON leagues.id = teams.league_id
+    WHERE leagues."leagueId" = $1 AND (teams."owners" = $2 OR teams."teamId" = $3)
+);
+
+-- name: ListClaimedTeams :many
+SELECT DISTINCT teams."id", teams."league_id", leagues."leagueId" AS espn_league_id, teams."year", teams."teamName", teams."teamAbbrv", teams."owners", teams."wins", teams."losses", teams."ties", teams."finalStanding", teams."logoUrl"
+FROM team_claims
+JOIN leagues ON leagues."leagueId" = team_claims.league_id
+JOIN teams ON teams.league_id = leagues.id
+WHERE team_claims.user_id = $1
+    AND team_claims.status = 'approved'
+    AND (teams."owners" = team_claims.owner OR teams."teamId" = team_claims.team_id)
+ORDER BY teams."year" DESC, teams."teamName";
+
+-- name: ListLeagueOwners :many
+SELECT DISTINCT leagues."leagueId", teams."owners"
+FROM teams
+JOIN leagues ON leagues.id = teams.league_id
+WHERE teams."owners" IS NOT NULL
+ORDER BY leagues."leagueId", teams."owners";
+
+-- name: InsertApprovedTeamClaim :one
+INSERT INTO team_claims (user_id, league_id, owner, team_id, status, message, decided_by)
+VALUES ($1, $2, $3, $4, 'approved', $5, $6)
+ON CONFLICT DO NOTHING
+RETURNING id;
@@ -1,4 +1,27 @@
 -- name: GetTeamById :one
-SELECT "id", "league_id", "year", "teamAbbrv", "owners", "divisionId", "divisionName", "wins", "losses", "ties", "pointsFor", "pointsAgainst", "waiverRank", "acquisitions", "acquisitionBudgetSpent", "drops", "trades", "logoUrl" 
+SELECT "id", "league_id", "teamId", "year", "teamAbbrv", "teamName", "owners", "divisionId", "divisionName", "wins", "losses", "ties", "pointsFor", "pointsAgainst", "waiverRank", "acquisitions", "acquisitionBudgetSpent", "drops", "trades", "logoUrl" 
 FROM "teams" 
-WHERE "id" = $1;
\ No newline at end of file
+WHERE "id" = $1;
+
+-- name: ListTeamsByIDs :many
+SELECT "id", "league_id", "teamId", "year", "teamAbbrv", "teamName", "owners", "divisionId", "divisionName", "wins", "losses", "ties", "pointsFor", "pointsAgainst", "waiverRank", "acquisitions", "acquisitionBudgetSpent", "drops", "trades", "streakType", "streakLength", "standing", "finalStanding", "draftProjRank", "playoffPct", "logoUrl"
+FROM "teams"
+WHERE "id" = ANY(@ids::int[]);
+
+-- name: ListTeamsByLeagueIDs :many
+SELECT "id", "league_id", "teamId", "year", "teamAbbrv", "teamName", "owners", "divisionId", "divisionName", "wins", "losses", "ties", "pointsFor", "pointsAgainst", "waiverRank", "acquisitions", "acquisitionBudgetSpent", "drops", "trades", "streakType", "streakLength", "standing", "finalStanding", "draftProjRank", "playoffPct", "logoUrl"
+FROM "teams"
+WHERE "league_id" = ANY(@league_ids::int[])
+ORDER BY "league_id", "teamId";
+
+-- name: ListTeamsByOwners :many
+SELECT "id", "league_id", "teamId", "year", "teamAbbrv", "teamName", "owners", "divisionId", "divisionName", "wins", "losses", "ties", "pointsFor", "pointsAgainst", "waiverRank", "acquisitions", "acquisitionBudgetSpent", "drops", "trades", "streakType", "streakLength", "standing", "finalStanding", "draftProjRank", "playoffPct", "logoUrl"
+FROM "teams"
+WHERE "owners" = ANY(@owners::text[])
+ORDER BY "year", "teamId";
+
+-- name: ListOwners :many
+SELECT DISTINCT "owners"
+FROM "teams"
+WHERE "owners" IS NOT NULL
+ORDER BY "owners";
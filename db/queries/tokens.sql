@@ -4,4 +4,32 @@ VALUES ($1, $2, $3, $4);
 
 -- name: DeleteToken :exec
 DELETE FROM tokens 
-WHERE scope = $1 AND user_id = $2;
\ No newline at end of file
+WHERE scope = $1 AND user_id = $2;
+
+-- name: InsertAPIToken :one
+INSERT INTO tokens (hash, user_id, expiry, scope, name)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, created_at;
+
+-- name: ListAPITokens :many
+SELECT id, name, scope, expiry, created_at, last_used_at
+FROM tokens
+WHERE user_id = @user_id AND scope = ANY(@scopes::text[])
+ORDER BY created_at DESC, id DESC;
+
+-- name: DeleteAPIToken :execrows
+DELETE FROM tokens
+WHERE id = @id AND user_id = @user_id AND scope = ANY(@scopes::text[]);
+
+-- name: GetUserForToken :one
+SELECT users.id, users.name, tokens.id AS token_id, tokens.scope
+FROM users
+JOIN tokens ON tokens.user_id = users.id
+WHERE tokens.hash = @hash
+    AND tokens.scope = ANY(@scopes::text[])
+    AND (tokens.expiry IS NULL OR tokens.expiry > NOW());
+
+-- name: TouchToken :exec
+UPDATE tokens
+SET last_used_at = NOW()
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute');
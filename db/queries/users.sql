@@ -1,32 +1,47 @@
--- name: UpsertUser :one
+-- name: CreateUser :one
 INSERT INTO users (
     name,
     email,
-    activated,
-    provider,
-    provider_id
+    activated
 )
 VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3
 )
-ON CONFLICT (provider, provider_id) DO UPDATE
-SET
-    name = EXCLUDED.name,
-    email = EXCLUDED.email,
-    activated = EXCLUDED.activated,
-    version = users.version + 1,
-    created_at = NOW()
 RETURNING id, created_at, version;
 
 -- name: GetUserByProvider :one
-SELECT id, name
-FROM users 
-WHERE provider = $1 AND provider_id = $2;
+SELECT users.id, users.name
+FROM users
+JOIN user_identities ON user_identities.user_id = users.id
+WHERE user_identities.provider = $1 AND user_identities.provider_id = $2;
 
--- -- name: GetUserByEmail :one
--- SELECT id, created_at, name, email, password_hash, activated, version
--- FROM users
--- WHERE email = $1;
+-- name: InsertUser :one
+INSERT INTO users (
+    name,
+    email,
+    password_hash,
+    activated
+)
+VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, created_at, version;
+
+-- name: GetUserByEmail :one
+SELECT id, created_at, name, email, password_hash, activated, version
+FROM users
+WHERE lower(email) = lower($1) AND password_hash IS NOT NULL;
+
+-- name: ActivateUser :one
+UPDATE users
+SET activated = true, version = version + 1
+WHERE id = $1
+RETURNING id, created_at, name, email, activated, version;
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET password_hash = $1, version = version + 1
+WHERE id = $2;
 
 -- -- name: UpdateUser :one
 -- UPDATE users 
@@ -79,6 +79,16 @@ CREATE TABLE "teams" (
 --     CONSTRAINT idx_player_position INDEX (position)
 -- );
 
+CREATE TABLE "players" (
+    "id" INTEGER PRIMARY KEY,
+    "espnId" INTEGER NOT NULL UNIQUE,
+    "name" VARCHAR(255) NOT NULL,
+    "position" VARCHAR(50)
+);
+
+CREATE INDEX "idx_player_name" ON "players" ("name");
+CREATE INDEX "idx_player_position" ON "players" ("position");
+
 -- CREATE TABLE drafts (
 --     id INTEGER PRIMARY KEY AUTOINCREMENT,
 --     team_id INTEGER NOT NULL,
@@ -96,6 +106,22 @@ CREATE TABLE "teams" (
 --     FOREIGN KEY (nominating_team_id) REFERENCES teams(id)
 -- );
 
+CREATE TABLE "drafts" (
+    "id" SERIAL PRIMARY KEY,
+    "team_id" INTEGER NOT NULL,
+    "player_id" INTEGER NOT NULL,
+    "overallPick" INTEGER NOT NULL,
+    "roundNum" INTEGER NOT NULL,
+    "roundPick" INTEGER NOT NULL,
+    "keeperStatus" BOOLEAN DEFAULT FALSE,
+    "bidAmount" INTEGER DEFAULT NULL,
+    "nominating_team_id" INTEGER DEFAULT NULL,
+    CONSTRAINT "uix_draft_pick" UNIQUE ("team_id", "player_id"),
+    FOREIGN KEY ("team_id") REFERENCES "teams"("id"),
+    FOREIGN KEY ("player_id") REFERENCES "players"("id"),
+    FOREIGN KEY ("nominating_team_id") REFERENCES "teams"("id")
+);
+
 -- CREATE TABLE matchups (
 --     id INTEGER PRIMARY KEY AUTOINCREMENT,
 --     week INTEGER NOT NULL,
@@ -112,6 +138,22 @@ CREATE TABLE "teams" (
 --     FOREIGN KEY (away_team_id) REFERENCES teams(id)
 -- );
 
+CREATE TABLE "matchups" (
+    "id" SERIAL PRIMARY KEY,
+    "week" INTEGER NOT NULL,
+    "home_team_id" INTEGER,
+    "away_team_id" INTEGER,
+    "homeScore" DOUBLE PRECISION,
+    "awayScore" DOUBLE PRECISION,
+    "isPlayoff" BOOLEAN DEFAULT FALSE,
+    "matchupType" VARCHAR(50) DEFAULT 'NONE',
+    CONSTRAINT "uix_matchup" UNIQUE ("week", "home_team_id", "away_team_id"),
+    FOREIGN KEY ("home_team_id") REFERENCES "teams"("id"),
+    FOREIGN KEY ("away_team_id") REFERENCES "teams"("id")
+);
+
+CREATE INDEX "idx_matchup_team_week" ON "matchups" ("home_team_id", "away_team_id", "week");
+
 -- CREATE TABLE activities (
 --     id INTEGER PRIMARY KEY AUTOINCREMENT,
 --     date BIGINT,
@@ -125,6 +167,29 @@ CREATE TABLE "teams" (
 --     FOREIGN KEY (player_id) REFERENCES players(id)
 -- );
 
+CREATE TABLE "activities" (
+    "id" SERIAL PRIMARY KEY,
+    "date" BIGINT,
+    "team_id" INTEGER,
+    "player_id" INTEGER NOT NULL,
+    "bidAmount" DOUBLE PRECISION,
+    "action" VARCHAR(50),
+    FOREIGN KEY ("team_id") REFERENCES "teams"("id"),
+    FOREIGN KEY ("player_id") REFERENCES "players"("id")
+);
+
+CREATE INDEX "idx_activity_team" ON "activities" ("team_id");
+
+-- archive_revision is a single-row counter bumped by triggers whenever an import
+-- touches the archive tables. It backs the ETag and Last-Modified headers.
+CREATE TABLE IF NOT EXISTS archive_revision (
+    id boolean PRIMARY KEY DEFAULT true CHECK (id),
+    revision bigint NOT NULL DEFAULT 1,
+    current_season integer NOT NULL DEFAULT 0,
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    last_import_seconds double precision NOT NULL DEFAULT 0
+);
+
 -- CREATE TABLE rosters (
 --     id INTEGER PRIMARY KEY AUTOINCREMENT,
 --     team_id INTEGER NOT NULL,
@@ -143,15 +208,97 @@ CREATE TABLE IF NOT EXISTS users (
     name text NOT NULL,
     email citext,
     activated bool NOT NULL,
+    version integer NOT NULL DEFAULT 1,
+    password_hash bytea
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_password_email_idx ON users (lower(email)) WHERE password_hash IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
     provider text NOT NULL,
     provider_id text NOT NULL,
-    version integer NOT NULL DEFAULT 1,
-    CONSTRAINT unique_provider_and_provider_id UNIQUE (provider, provider_id)
+    email citext,
+    email_verified bool NOT NULL DEFAULT false,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    last_login_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_identity_provider_and_provider_id UNIQUE (provider, provider_id)
 );
 
+CREATE INDEX IF NOT EXISTS user_identities_user_id_idx ON user_identities (user_id);
+CREATE INDEX IF NOT EXISTS user_identities_verified_email_idx ON user_identities (lower(email)) WHERE email_verified;
+
 CREATE TABLE IF NOT EXISTS tokens (
     hash bytea PRIMARY KEY,
     user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
-    expiry timestamp(0) with time zone NOT NULL,
-    scope text NOT NULL
-);
\ No newline at end of file
+    expiry timestamp(0) with time zone,
+    scope text NOT NULL,
+    id bigserial UNIQUE,
+    name text NOT NULL DEFAULT '',
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    last_used_at timestamp(0) with time zone
+);
+
+CREATE INDEX IF NOT EXISTS tokens_user_id_scope_idx ON tokens (user_id, scope);
+
+CREATE TABLE IF NOT EXISTS league_roles (
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    league_id integer NOT NULL,
+    role text NOT NULL CHECK (role IN ('admin', 'commissioner', 'member', 'viewer')),
+    granted_by bigint REFERENCES users ON DELETE SET NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, league_id)
+);
+
+CREATE INDEX IF NOT EXISTS league_roles_league_id_idx ON league_roles (league_id);
+
+CREATE TABLE IF NOT EXISTS team_claims (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    league_id integer NOT NULL,
+    owner text,
+    team_id integer,
+    status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'revoked', 'withdrawn')),
+    message text NOT NULL DEFAULT '',
+    decided_by bigint REFERENCES users ON DELETE SET NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    CHECK ((owner IS NULL) <> (team_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS team_claims_open_idx ON team_claims (user_id, league_id, COALESCE(owner, ''), COALESCE(team_id, 0))
+    WHERE status IN ('pending', 'approved');
+CREATE INDEX IF NOT EXISTS team_claims_league_id_idx ON team_claims (league_id, status);
+
+CREATE TABLE IF NOT EXISTS team_claim_events (
+    id bigserial PRIMARY KEY,
+    claim_id bigint NOT NULL REFERENCES team_claims ON DELETE CASCADE,
+    status text NOT NULL,
+    actor_id bigint REFERENCES users ON DELETE SET NULL,
+    note text NOT NULL DEFAULT '',
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS team_claim_events_claim_id_idx ON team_claim_events (claim_id);
+
+CREATE TABLE IF NOT EXISTS league_invitations (
+    id bigserial PRIMARY KEY,
+    hash bytea NOT NULL UNIQUE,
+    league_id integer NOT NULL,
+    email citext NOT NULL,
+    role text NOT NULL CHECK (role IN ('admin', 'commissioner', 'member', 'viewer')),
+    owner text,
+    team_id integer,
+    invited_by bigint REFERENCES users ON DELETE SET NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    expires_at timestamp(0) with time zone NOT NULL,
+    accepted_at timestamp(0) with time zone,
+    accepted_by bigint REFERENCES users ON DELETE SET NULL,
+    revoked_at timestamp(0) with time zone,
+    CHECK (owner IS NULL OR team_id IS NULL)
+);
+
+CREATE INDEX IF NOT EXISTS league_invitations_league_id_idx ON league_invitations (league_id);
+CREATE INDEX IF NOT EXISTS league_invitations_open_email_idx ON league_invitations (email) WHERE accepted_at IS NULL AND revoked_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,273 @@
+// Package cache stores the results of expensive archive queries and rendered
+// template fragments in Redis.
+//
+// Every key embeds the archive revision it was computed from, so an import or a
+// correction implicitly invalidates everything cached before it: the application
+// simply starts asking for keys under the new revision. Listen keeps the revision
+// current by subscribing to the notifications sent by the archive_revision trigger,
+// and clears out the keys of the revision it replaces.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Channel is the Postgres notification channel the archive_revision trigger
+// publishes the new revision on.
+const Channel = "archive_revision"
+
+const keyPrefix = "cache:"
+
+var tracer = otel.Tracer("github.com/layer8s/home-dashboard-app/internal/cache")
+
+// Cache is a revision-aware read-through cache. The zero revision is valid but
+// means nothing cached before the first SetVersion call will be found afterwards.
+type Cache struct {
+	client  *redis.Client
+	logger  *slog.Logger
+	ttl     time.Duration
+	version atomic.Int64
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+}
+
+// New returns a cache storing entries in client for at most ttl.
+func New(client *redis.Client, logger *slog.Logger, ttl time.Duration) *Cache {
+	return &Cache{client: client, logger: logger, ttl: ttl}
+}
+
+// Version returns the archive revision new keys are written under.
+func (c *Cache) Version() int64 {
+	return c.version.Load()
+}
+
+// SetVersion moves the cache to revision v and reports the revision it replaced.
+func (c *Cache) SetVersion(v int64) int64 {
+	return c.version.Swap(v)
+}
+
+// Key builds a versioned key for the named entry. The parts identify the entry
+// within name, usually the query parameters, and are hashed to keep keys short.
+func (c *Cache) Key(name string, parts ...any) string {
+	return c.KeyAt(c.Version(), name, parts...)
+}
+
+// KeyAt builds the key for the named entry under revision v rather than the
+// current one. Handlers that have read the revision themselves, say for an ETag,
+// use it so the entry they serve is the one that revision describes, whether or
+// not the notification moving the cache on has arrived yet.
+func (c *Cache) KeyAt(v int64, name string, parts ...any) string {
+	sum := sha256.Sum256([]byte(fmt.Sprint(parts...)))
+	return fmt.Sprintf("%sv%d:%s:%s", keyPrefix, v, name, hex.EncodeToString(sum[:12]))
+}
+
+// Fetch returns the value cached under key, or calls load and caches its result.
+// Redis failures are logged and treated as misses, so a cache outage only costs
+// the queries it would have saved.
+func Fetch[T any](ctx context.Context, c *Cache, key string, load func() (T, error)) (T, error) {
+	var value T
+
+	data, err := c.get(ctx, key)
+	if err == nil {
+		if err = json.Unmarshal(data, &value); err == nil {
+			return value, nil
+		}
+		c.logger.Warn("discarding unreadable cache entry", "key", key, "error", err)
+	}
+
+	value, err = load()
+	if err != nil {
+		return value, err
+	}
+
+	if data, err := json.Marshal(value); err == nil {
+		c.set(ctx, key, data)
+	}
+	return value, nil
+}
+
+// Renderer is satisfied by templ.Component.
+type Renderer interface {
+	Render(ctx context.Context, w io.Writer) error
+}
+
+// HTML is a rendered fragment. It implements Renderer, so a cached fragment can be
+// passed anywhere a templ.Component is expected.
+type HTML []byte
+
+func (h HTML) Render(ctx context.Context, w io.Writer) error {
+	_, err := w.Write(h)
+	return err
+}
+
+// Fragment returns the fragment cached under key, or renders the component
+// returned by build and caches the output. build is only called on a miss, so it
+// is the place to run the queries the fragment needs.
+func (c *Cache) Fragment(ctx context.Context, key string, build func() (Renderer, error)) (HTML, error) {
+	if data, err := c.get(ctx, key); err == nil {
+		return HTML(data), nil
+	}
+
+	component, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	renderCtx, span := tracer.Start(ctx, "render fragment", trace.WithAttributes(attribute.String("cache.key", key)))
+	err = component.Render(renderCtx, &buf)
+	span.End()
+	if err != nil {
+		return nil, err
+	}
+
+	c.set(ctx, key, buf.Bytes())
+	return HTML(buf.Bytes()), nil
+}
+
+func (c *Cache) get(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.client.Get(ctx, key).Bytes()
+	switch {
+	case err == nil:
+		c.hits.Add(1)
+	case errors.Is(err, redis.Nil):
+		c.misses.Add(1)
+	default:
+		c.misses.Add(1)
+		c.logger.Warn("cache read failed", "key", key, "error", err)
+	}
+	return data, err
+}
+
+func (c *Cache) set(ctx context.Context, key string, data []byte) {
+	if err := c.client.Set(ctx, key, data, c.ttl).Err(); err != nil {
+		c.logger.Warn("cache write failed", "key", key, "error", err)
+	}
+}
+
+// Listen subscribes to archive revision notifications on the Postgres database at
+// dsn and moves the cache to each new revision as the import that caused it
+// commits. If the connection drops, notifications may have been missed, so the
+// revision is re-read with current once it's re-established. Should subscribing
+// fail, it's retried with backoff, logging each failure. Listen blocks until ctx
+// is cancelled.
+func (c *Cache) Listen(ctx context.Context, dsn string, current func(context.Context) (int64, error)) {
+	const maxBackoff = time.Minute
+	backoff := time.Second
+	for {
+		err := c.listen(ctx, dsn, current)
+		if ctx.Err() != nil {
+			return
+		}
+		c.logger.Error("cache invalidation listener failed, retrying", "error", err, "retry_in", backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxBackoff)
+	}
+}
+
+func (c *Cache) listen(ctx context.Context, dsn string, current func(context.Context) (int64, error)) error {
+	listener := pq.NewListener(dsn, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			c.logger.Warn("cache listener connection problem", "error", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(Channel); err != nil {
+		return err
+	}
+	// Revisions may have moved on while nothing was listening.
+	if version, err := current(ctx); err != nil {
+		c.logger.Error("failed to read archive revision", "error", err)
+	} else {
+		c.advance(ctx, version)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-listener.Notify:
+			var version int64
+			var err error
+			if n == nil {
+				// The connection was re-established; ask for the revision instead.
+				version, err = current(ctx)
+			} else {
+				version, err = strconv.ParseInt(n.Extra, 10, 64)
+			}
+			if err != nil {
+				c.logger.Error("failed to read archive revision", "error", err)
+				continue
+			}
+			c.advance(ctx, version)
+		case <-time.After(90 * time.Second):
+			// Check the connection is still alive so a silent drop is noticed.
+			go listener.Ping()
+		}
+	}
+}
+
+func (c *Cache) advance(ctx context.Context, version int64) {
+	previous := c.SetVersion(version)
+	if previous == version {
+		return
+	}
+	c.logger.Info("archive revision changed, invalidating cache", "from", previous, "to", version)
+
+	// Entries would expire on their own, but there's no point holding on to them.
+	pattern := fmt.Sprintf("%sv%d:*", keyPrefix, previous)
+	iter := c.client.Scan(ctx, 0, pattern, 500).Iterator()
+	var purged int
+	for iter.Next(ctx) {
+		if err := c.client.Del(ctx, iter.Val()).Err(); err == nil {
+			purged++
+		}
+	}
+	if err := iter.Err(); err != nil {
+		c.logger.Warn("failed to purge stale cache entries", "revision", previous, "error", err)
+	}
+	c.logger.Info("purged stale cache entries", "revision", previous, "count", purged)
+}
+
+// ReportStats logs the hit rate every interval until ctx is cancelled. The
+// counters are cumulative since the process started.
+func (c *Cache) ReportStats(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			hits, misses := c.hits.Load(), c.misses.Load()
+			rate := 0.0
+			if total := hits + misses; total > 0 {
+				rate = float64(hits) / float64(total)
+			}
+			c.logger.Info("cache stats", "hits", hits, "misses", misses, "hit_rate", strconv.FormatFloat(rate, 'f', 3, 64), "revision", c.Version())
+		}
+	}
+}
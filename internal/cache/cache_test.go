@@ -0,0 +1,18 @@
+package cache
+
+import "testing"
+
+func TestKeyAt(t *testing.T) {
+	c := New(nil, nil, 0)
+	c.SetVersion(3)
+
+	if got, want := c.Key("leagues", "ASC", 1), c.KeyAt(3, "leagues", "ASC", 1); got != want {
+		t.Errorf("Key = %q, want %q", got, want)
+	}
+	if c.KeyAt(4, "leagues", "ASC", 1) == c.Key("leagues", "ASC", 1) {
+		t.Error("keys for different revisions should differ")
+	}
+	if c.KeyAt(3, "leagues", "ASC", 1) == c.KeyAt(3, "leagues", "DESC", 1) {
+		t.Error("keys for different parts should differ")
+	}
+}
@@ -0,0 +1,628 @@
+// Package config loads the API server's configuration. Values are layered, each
+// layer overriding the one before it:
+//
+//  1. built-in defaults for the selected environment,
+//  2. a YAML or TOML file (chosen by extension), in which ${NAME} is replaced by
+//     the value of the environment variable NAME,
+//  3. environment variables, optionally loaded from a .env file,
+//  4. command-line flags.
+//
+// Everything is validated once all the layers have been applied, and every problem
+// is reported together rather than one at a time.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Environments lists the values accepted for Config.Env.
+var Environments = []string{"development", "staging", "production"}
+
+// Config is the complete server configuration. Path and Sources record where it
+// was loaded from, for logging.
+type Config struct {
+	Port    int      `yaml:"port" toml:"port"`
+	Env     string   `yaml:"env" toml:"env"`
+	DB      DB       `yaml:"db" toml:"db"`
+	Redis   Redis    `yaml:"redis" toml:"redis"`
+	Session Session  `yaml:"session" toml:"session"`
+	Mail    Mail     `yaml:"mail" toml:"mail"`
+	Auth    Auth     `yaml:"auth" toml:"auth"`
+	GraphQL GraphQL  `yaml:"graphql" toml:"graphql"`
+	Cache   Cache    `yaml:"cache" toml:"cache"`
+	Limiter Limiter  `yaml:"limiter" toml:"limiter"`
+	Log     Log      `yaml:"log" toml:"log"`
+	Health  Health   `yaml:"health" toml:"health"`
+	Tracing Tracing  `yaml:"tracing" toml:"tracing"`
+	Path    string   `yaml:"-" toml:"-"`
+	Sources []string `yaml:"-" toml:"-"`
+}
+
+type DB struct {
+	DSN          string        `yaml:"dsn" toml:"dsn"`
+	MaxOpenConns int           `yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns int           `yaml:"max_idle_conns" toml:"max_idle_conns"`
+	MaxIdleTime  time.Duration `yaml:"max_idle_time" toml:"max_idle_time"`
+}
+
+type Redis struct {
+	Addr     string `yaml:"addr" toml:"addr"`
+	Password string `yaml:"password" toml:"password"`
+	DB       int    `yaml:"db" toml:"db"`
+}
+
+type Session struct {
+	Key string `yaml:"key" toml:"key"`
+	// MaxAge is how long a session lasts after sign-in, however busy it is.
+	MaxAge time.Duration `yaml:"max_age" toml:"max_age"`
+	// IdleTimeout signs out a session that hasn't been used for this long. Each
+	// request pushes it back. Zero turns it off.
+	IdleTimeout time.Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+	// RememberMeMaxAge replaces MaxAge for sign-ins with "keep me signed in"
+	// ticked. Those sessions don't time out when idle. Zero hides the option.
+	RememberMeMaxAge time.Duration `yaml:"remember_me_max_age" toml:"remember_me_max_age"`
+}
+
+type Mail struct {
+	SendGridKey string `yaml:"sendgrid_key" toml:"sendgrid_key"`
+	Sender      string `yaml:"sender" toml:"sender"`
+}
+
+type Auth struct {
+	// BaseCallbackURL is the externally visible URL of the server, used to build
+	// OAuth redirect URLs. It defaults to http://localhost:<port>.
+	BaseCallbackURL string `yaml:"base_callback_url" toml:"base_callback_url"`
+	// PostLogoutRedirectURL is where providers send the user back to after
+	// signing them out. It defaults to the root of BaseCallbackURL.
+	PostLogoutRedirectURL string     `yaml:"post_logout_redirect_url" toml:"post_logout_redirect_url"`
+	Providers             []Provider `yaml:"providers" toml:"providers"`
+	// Admins are the email addresses of site administrators, who hold the admin
+	// role in every league. An address only counts once a provider has verified
+	// it or, for password accounts, once the account is activated.
+	Admins []string `yaml:"admins" toml:"admins"`
+}
+
+// Provider declares a login provider. See providers.go for the provider types,
+// presets and the defaults applied to each.
+type Provider struct {
+	Name string `yaml:"name" toml:"name"`
+	// Type is "oidc" for OpenID Connect providers, whose endpoints are discovered
+	// from Issuer, or "oauth2" for plain OAuth2 providers, whose endpoints are
+	// given explicitly and whose user is read from UserInfoURL.
+	Type string `yaml:"type" toml:"type"`
+	// Preset fills in the endpoints, scopes and claims of a well-known provider,
+	// such as "github". Anything set explicitly takes precedence.
+	Preset       string       `yaml:"preset" toml:"preset"`
+	Issuer       string       `yaml:"issuer" toml:"issuer"`
+	ClientID     string       `yaml:"client_id" toml:"client_id"`
+	ClientSecret string       `yaml:"client_secret" toml:"client_secret"`
+	Scopes       []string     `yaml:"scopes" toml:"scopes"`
+	AuthURL      string       `yaml:"auth_url" toml:"auth_url"`
+	TokenURL     string       `yaml:"token_url" toml:"token_url"`
+	UserInfoURL  string       `yaml:"userinfo_url" toml:"userinfo_url"`
+	Claims       ClaimMapping `yaml:"claims" toml:"claims"`
+	// EndSessionURL overrides the end_session_endpoint from discovery, for
+	// providers that support RP-initiated logout without advertising it.
+	EndSessionURL string `yaml:"end_session_url" toml:"end_session_url"`
+}
+
+// ClaimMapping names the claims, or userinfo fields, that hold each user
+// attribute. Nested values are addressed with dots, for example "profile.email".
+type ClaimMapping struct {
+	Subject  string `yaml:"subject" toml:"subject"`
+	Email    string `yaml:"email" toml:"email"`
+	Name     string `yaml:"name" toml:"name"`
+	Username string `yaml:"username" toml:"username"`
+	// EmailVerified names a boolean claim saying the provider has verified the
+	// email address. Without one, addresses are treated as unverified and never
+	// offered for account linking.
+	EmailVerified string `yaml:"email_verified" toml:"email_verified"`
+}
+
+type GraphQL struct {
+	MaxDepth      int `yaml:"max_depth" toml:"max_depth"`
+	MaxComplexity int `yaml:"max_complexity" toml:"max_complexity"`
+}
+
+type Cache struct {
+	TTL           time.Duration `yaml:"ttl" toml:"ttl"`
+	StatsInterval time.Duration `yaml:"stats_interval" toml:"stats_interval"`
+}
+
+type Limiter struct {
+	Enabled bool                 `yaml:"enabled" toml:"enabled"`
+	Groups  map[string]RateLimit `yaml:"groups" toml:"groups"`
+}
+
+// RateLimit is the number of requests a client may make to a route group within a
+// sliding window. It's written as requests/window, for example 120/1m.
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+func (l RateLimit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Window)
+}
+
+func (l RateLimit) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+func (l *RateLimit) UnmarshalText(text []byte) error {
+	count, window, ok := strings.Cut(string(text), "/")
+	if !ok {
+		return fmt.Errorf("%q must have the form requests/window", text)
+	}
+
+	requests, err := strconv.Atoi(count)
+	if err != nil || requests <= 0 {
+		return fmt.Errorf("%q must allow a positive number of requests", text)
+	}
+
+	d, err := time.ParseDuration(window)
+	if err != nil || d < time.Second {
+		return fmt.Errorf("%q must have a window of at least one second", text)
+	}
+
+	l.Requests, l.Window = requests, d
+	return nil
+}
+
+type Log struct {
+	Format    string `yaml:"format" toml:"format"`
+	AccessLog bool   `yaml:"access_log" toml:"access_log"`
+}
+
+type Health struct {
+	// Optional lists the readiness checks allowed to fail: db, redis, oidc (every
+	// provider) or oidc:<provider>.
+	Optional []string `yaml:"optional" toml:"optional"`
+}
+
+type Tracing struct {
+	Exporter    string  `yaml:"exporter" toml:"exporter"`
+	Endpoint    string  `yaml:"endpoint" toml:"endpoint"`
+	Insecure    bool    `yaml:"insecure" toml:"insecure"`
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+}
+
+// Defaults returns the built-in configuration for env.
+func Defaults(env string) Config {
+	cfg := Config{
+		Port: 4000,
+		Env:  env,
+		DB: DB{
+			MaxOpenConns: 25,
+			MaxIdleConns: 25,
+			MaxIdleTime:  15 * time.Minute,
+		},
+		Redis: Redis{Addr: "localhost:6379"},
+		Session: Session{
+			MaxAge:           24 * time.Hour,
+			IdleTimeout:      2 * time.Hour,
+			RememberMeMaxAge: 30 * 24 * time.Hour,
+		},
+		Mail: Mail{Sender: "FFArchive <robert@litts.org>"},
+		GraphQL: GraphQL{
+			MaxDepth:      8,
+			MaxComplexity: 5000,
+		},
+		Cache: Cache{
+			TTL:           time.Hour,
+			StatsInterval: 5 * time.Minute,
+		},
+		Limiter: Limiter{
+			Enabled: true,
+			Groups: map[string]RateLimit{
+				"archive": {Requests: 120, Window: time.Minute},
+				"graphql": {Requests: 30, Window: time.Minute},
+				"auth":    {Requests: 20, Window: time.Minute},
+				// Sign-in links, per client IP and per email address asked for.
+				"magic_link":       {Requests: 10, Window: 15 * time.Minute},
+				"magic_link_email": {Requests: 3, Window: 15 * time.Minute},
+			},
+		},
+		Log:    Log{Format: "text", AccessLog: true},
+		Health: Health{Optional: []string{"oidc"}},
+		Tracing: Tracing{
+			Exporter:    "none",
+			Endpoint:    "localhost:4318",
+			Insecure:    true,
+			SampleRatio: 1,
+		},
+	}
+
+	// Deployed environments log in a format their collectors can parse and expect
+	// traces to leave the host over TLS.
+	switch env {
+	case "staging":
+		cfg.Log.Format = "json"
+		cfg.Tracing.Insecure = false
+	case "production":
+		cfg.Log.Format = "json"
+		cfg.Tracing.Insecure = false
+		cfg.Tracing.SampleRatio = 0.1
+	}
+
+	return cfg
+}
+
+// Load builds the configuration from the defaults, the config file, the environment
+// and args, which are the command-line arguments without the program name. The
+// file is taken from -config, then $CONFIG_FILE, then ./config.yaml or
+// ./config.toml if either exists.
+func Load(args []string) (*Config, error) {
+	// A missing .env file is fine; the variables may be set some other way.
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("loading .env: %w", err)
+	}
+
+	fs := flag.NewFlagSet("api", flag.ContinueOnError)
+	path := fs.String("config", os.Getenv("CONFIG_FILE"), "Path to a YAML or TOML config file")
+	flags := registerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *path == "" {
+		for _, candidate := range []string{"config.yaml", "config.toml"} {
+			if _, err := os.Stat(candidate); err == nil {
+				*path = candidate
+				break
+			}
+		}
+	}
+
+	var file []byte
+	if *path != "" {
+		data, err := os.ReadFile(*path)
+		if err != nil {
+			return nil, err
+		}
+		file = expandEnv(data)
+	}
+
+	// The environment picks the defaults, so it has to be known before anything
+	// else is applied. Later layers take precedence, as they do for other values.
+	env := "development"
+	if file != nil {
+		var peek struct {
+			Env string `yaml:"env" toml:"env"`
+		}
+		if err := decode(*path, file, &peek); err != nil {
+			return nil, err
+		}
+		if peek.Env != "" {
+			env = peek.Env
+		}
+	}
+	if v := os.Getenv("ENV"); v != "" {
+		env = strings.TrimSpace(v)
+	}
+	if flags.env != "" {
+		env = flags.env
+	}
+
+	cfg := Defaults(env)
+	cfg.Sources = append(cfg.Sources, "defaults:"+env)
+
+	if file != nil {
+		if err := decode(*path, file, &cfg); err != nil {
+			return nil, err
+		}
+		cfg.Path = *path
+		cfg.Sources = append(cfg.Sources, "file:"+*path)
+	}
+	cfg.Env = env
+
+	var errs []error
+	if applied, err := applyEnv(&cfg); err != nil {
+		errs = append(errs, err)
+	} else if applied {
+		cfg.Sources = append(cfg.Sources, "env")
+	}
+
+	for _, set := range flags.setters {
+		if err := set(&cfg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(flags.setters) > 0 {
+		cfg.Sources = append(cfg.Sources, "flags")
+	}
+
+	// Setups from before the config file declare their providers only through the
+	// environment; keep them working until they move to auth.providers.
+	if len(cfg.Auth.Providers) == 0 {
+		if legacy := legacyProviders(); len(legacy) > 0 {
+			cfg.Auth.Providers = legacy
+			cfg.Sources = append(cfg.Sources, "env:legacy-providers")
+		}
+	}
+
+	for i := range cfg.Auth.Providers {
+		cfg.Auth.Providers[i].applyDefaults()
+	}
+
+	if cfg.Auth.BaseCallbackURL == "" {
+		cfg.Auth.BaseCallbackURL = fmt.Sprintf("http://localhost:%d", cfg.Port)
+	}
+	if cfg.Auth.PostLogoutRedirectURL == "" {
+		cfg.Auth.PostLogoutRedirectURL = strings.TrimSuffix(cfg.Auth.BaseCallbackURL, "/") + "/"
+	}
+	for i, email := range cfg.Auth.Admins {
+		cfg.Auth.Admins[i] = strings.ToLower(strings.TrimSpace(email))
+	}
+
+	errs = append(errs, cfg.Validate())
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func decode(path string, data []byte, v any) error {
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, v)
+	case ".toml":
+		err = toml.Unmarshal(data, v)
+	default:
+		return fmt.Errorf("config file %s: unsupported format; use .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv replaces ${NAME} with the value of the environment variable NAME. Bare
+// $NAME is left alone so secrets containing a dollar sign survive.
+func expandEnv(data []byte) []byte {
+	return envReference.ReplaceAllFunc(data, func(ref []byte) []byte {
+		return []byte(os.Getenv(string(ref[2 : len(ref)-1])))
+	})
+}
+
+// envVars maps the environment variables read by applyEnv to the value they set.
+// The names are the ones the server has always used.
+var envVars = []struct {
+	name string
+	set  func(cfg *Config, value string) error
+}{
+	{"PORT", func(cfg *Config, v string) (err error) { cfg.Port, err = strconv.Atoi(v); return }},
+	{"DB_URL", func(cfg *Config, v string) error { cfg.DB.DSN = v; return nil }},
+	{"DB_MAX_OPEN_CONNS", func(cfg *Config, v string) (err error) { cfg.DB.MaxOpenConns, err = strconv.Atoi(v); return }},
+	{"DB_MAX_IDLE_CONNS", func(cfg *Config, v string) (err error) { cfg.DB.MaxIdleConns, err = strconv.Atoi(v); return }},
+	{"DB_MAX_IDLE_TIME", func(cfg *Config, v string) (err error) { cfg.DB.MaxIdleTime, err = time.ParseDuration(v); return }},
+	{"REDIS_ADDR", func(cfg *Config, v string) error { cfg.Redis.Addr = v; return nil }},
+	{"REDIS_PASSWORD", func(cfg *Config, v string) error { cfg.Redis.Password = v; return nil }},
+	{"REDIS_DB", func(cfg *Config, v string) (err error) { cfg.Redis.DB, err = strconv.Atoi(v); return }},
+	{"SESSION_KEY", func(cfg *Config, v string) error { cfg.Session.Key = v; return nil }},
+	{"SESSION_MAX_AGE", func(cfg *Config, v string) (err error) { cfg.Session.MaxAge, err = time.ParseDuration(v); return }},
+	{"SESSION_IDLE_TIMEOUT", func(cfg *Config, v string) (err error) { cfg.Session.IdleTimeout, err = time.ParseDuration(v); return }},
+	{"SESSION_REMEMBER_ME_MAX_AGE", func(cfg *Config, v string) (err error) {
+		cfg.Session.RememberMeMaxAge, err = time.ParseDuration(v)
+		return
+	}},
+	{"SENDGRID_API_KEY", func(cfg *Config, v string) error { cfg.Mail.SendGridKey = v; return nil }},
+	{"BASE_CALLBACK_URL", func(cfg *Config, v string) error { cfg.Auth.BaseCallbackURL = v; return nil }},
+	{"POST_LOGOUT_REDIRECT_URL", func(cfg *Config, v string) error { cfg.Auth.PostLogoutRedirectURL = v; return nil }},
+	{"ADMIN_EMAILS", func(cfg *Config, v string) error { cfg.Auth.Admins = splitList(v); return nil }},
+}
+
+func applyEnv(cfg *Config) (bool, error) {
+	var errs []error
+	applied := false
+	for _, ev := range envVars {
+		// An empty variable is treated as unset, as it always has been.
+		value := strings.TrimSpace(os.Getenv(ev.name))
+		if value == "" {
+			continue
+		}
+		if err := ev.set(cfg, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ev.name, err))
+			continue
+		}
+		applied = true
+	}
+	return applied, errors.Join(errs...)
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+type flagValues struct {
+	env     string
+	setters []func(*Config) error
+}
+
+// registerFlags defines the command-line flags. Values are recorded as setters and
+// applied after the file and environment, so only flags actually given override
+// them.
+func registerFlags(fs *flag.FlagSet) *flagValues {
+	fv := &flagValues{}
+
+	str := func(name, usage string, set func(*Config, string)) {
+		fs.Func(name, usage, func(v string) error {
+			fv.setters = append(fv.setters, func(cfg *Config) error { set(cfg, v); return nil })
+			return nil
+		})
+	}
+	num := func(name, usage string, set func(*Config, int)) {
+		fs.Func(name, usage, func(v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return err
+			}
+			fv.setters = append(fv.setters, func(cfg *Config) error { set(cfg, n); return nil })
+			return nil
+		})
+	}
+	dur := func(name, usage string, set func(*Config, time.Duration)) {
+		fs.Func(name, usage, func(v string) error {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return err
+			}
+			fv.setters = append(fv.setters, func(cfg *Config) error { set(cfg, d); return nil })
+			return nil
+		})
+	}
+	boolean := func(name, usage string, set func(*Config, bool)) {
+		fs.BoolFunc(name, usage, func(v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return err
+			}
+			fv.setters = append(fv.setters, func(cfg *Config) error { set(cfg, b); return nil })
+			return nil
+		})
+	}
+
+	num("port", "API server port", func(c *Config, v int) { c.Port = v })
+	fs.Func("env", "Environment (development|staging|production)", func(v string) error {
+		fv.env = v
+		return nil
+	})
+	str("db-dsn", "PostgreSQL DSN", func(c *Config, v string) { c.DB.DSN = v })
+	num("db-max-open-conns", "PostgreSQL max open connections", func(c *Config, v int) { c.DB.MaxOpenConns = v })
+	num("db-max-idle-conns", "PostgreSQL max idle connections", func(c *Config, v int) { c.DB.MaxIdleConns = v })
+	dur("db-max-idle-time", "PostgreSQL max connection idle time", func(c *Config, v time.Duration) { c.DB.MaxIdleTime = v })
+	dur("session-max-age", "How long a session lasts after sign-in", func(c *Config, v time.Duration) { c.Session.MaxAge = v })
+	dur("session-idle-timeout", "Sign out sessions unused for this long (0 disables)", func(c *Config, v time.Duration) { c.Session.IdleTimeout = v })
+	dur("session-remember-me-max-age", "How long \"keep me signed in\" sessions last (0 disables)", func(c *Config, v time.Duration) { c.Session.RememberMeMaxAge = v })
+	num("graphql-max-depth", "GraphQL maximum query depth", func(c *Config, v int) { c.GraphQL.MaxDepth = v })
+	num("graphql-max-complexity", "GraphQL maximum query complexity", func(c *Config, v int) { c.GraphQL.MaxComplexity = v })
+	dur("cache-ttl", "Maximum lifetime of cached queries and fragments", func(c *Config, v time.Duration) { c.Cache.TTL = v })
+	dur("cache-stats-interval", "How often to log cache hit rates", func(c *Config, v time.Duration) { c.Cache.StatsInterval = v })
+	boolean("limiter-enabled", "Enable rate limiting", func(c *Config, v bool) { c.Limiter.Enabled = v })
+	fs.Func("rate-limit", "Rate limit for a route group as group=requests/window, e.g. archive=120/1m (repeatable)", func(v string) error {
+		group, spec, ok := strings.Cut(v, "=")
+		if !ok || group == "" {
+			return fmt.Errorf("%q must have the form group=requests/window", v)
+		}
+		var limit RateLimit
+		if err := limit.UnmarshalText([]byte(spec)); err != nil {
+			return err
+		}
+		fv.setters = append(fv.setters, func(cfg *Config) error {
+			if cfg.Limiter.Groups == nil {
+				cfg.Limiter.Groups = make(map[string]RateLimit)
+			}
+			cfg.Limiter.Groups[group] = limit
+			return nil
+		})
+		return nil
+	})
+	str("log-format", "Log format (text|json)", func(c *Config, v string) { c.Log.Format = v })
+	boolean("access-log", "Log every request once it has been served", func(c *Config, v bool) { c.Log.AccessLog = v })
+	str("health-optional", "Comma-separated readiness checks allowed to fail (db, redis, oidc, oidc:<provider>)", func(c *Config, v string) {
+		c.Health.Optional = splitList(v)
+	})
+	str("trace-exporter", "Trace exporter (none|otlp|stdout)", func(c *Config, v string) { c.Tracing.Exporter = v })
+	str("trace-endpoint", "OTLP/HTTP collector endpoint (host:port)", func(c *Config, v string) { c.Tracing.Endpoint = v })
+	boolean("trace-insecure", "Send OTLP traces over plain HTTP", func(c *Config, v bool) { c.Tracing.Insecure = v })
+	fs.Func("trace-sample-ratio", "Fraction of new traces to sample", func(v string) error {
+		ratio, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		fv.setters = append(fv.setters, func(cfg *Config) error { cfg.Tracing.SampleRatio = ratio; return nil })
+		return nil
+	})
+
+	return fv
+}
+
+// Validate checks the whole configuration and returns every problem found, joined
+// into one error.
+func (cfg *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, field, message string) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", field, message))
+		}
+	}
+
+	check(cfg.Port > 0 && cfg.Port <= 65535, "port", "must be between 1 and 65535")
+	check(slices.Contains(Environments, cfg.Env), "env", "must be one of "+strings.Join(Environments, ", "))
+
+	check(cfg.DB.DSN != "", "db.dsn", "must be provided (DB_URL)")
+	check(cfg.DB.MaxOpenConns > 0, "db.max_open_conns", "must be greater than zero")
+	check(cfg.DB.MaxIdleConns >= 0, "db.max_idle_conns", "must not be negative")
+	check(cfg.DB.MaxIdleTime > 0, "db.max_idle_time", "must be greater than zero")
+
+	check(cfg.Redis.Addr != "", "redis.addr", "must be provided (REDIS_ADDR)")
+	check(cfg.Redis.DB >= 0, "redis.db", "must not be negative")
+
+	check(cfg.Session.Key != "", "session.key", "must be provided (SESSION_KEY)")
+	check(cfg.Session.MaxAge >= time.Minute, "session.max_age", "must be at least one minute")
+	check(cfg.Session.IdleTimeout == 0 || cfg.Session.IdleTimeout >= time.Minute, "session.idle_timeout", "must be zero or at least one minute")
+	check(cfg.Session.RememberMeMaxAge == 0 || cfg.Session.RememberMeMaxAge >= cfg.Session.MaxAge, "session.remember_me_max_age", "must be zero or at least session.max_age")
+	if cfg.Env == "production" {
+		check(len(cfg.Session.Key) >= 32, "session.key", "must be at least 32 bytes in production")
+		check(cfg.Mail.SendGridKey != "", "mail.sendgrid_key", "must be provided in production (SENDGRID_API_KEY)")
+	}
+	check(cfg.Mail.Sender != "", "mail.sender", "must be provided")
+
+	seen := make(map[string]bool)
+	for i, p := range cfg.Auth.Providers {
+		field := fmt.Sprintf("auth.providers[%d]", i)
+		check(p.Name != "", field+".name", "must be provided")
+		check(!seen[p.Name], field+".name", fmt.Sprintf("%q is declared more than once", p.Name))
+		check(p.Name != PasswordProvider && p.Name != MagicLinkProvider, field+".name", fmt.Sprintf("%q is reserved for built-in sign-in", p.Name))
+		errs = append(errs, p.validate(field))
+		seen[p.Name] = true
+	}
+	for i, email := range cfg.Auth.Admins {
+		check(strings.Contains(email, "@"), fmt.Sprintf("auth.admins[%d]", i), "must be an email address")
+	}
+
+	check(cfg.GraphQL.MaxDepth > 0, "graphql.max_depth", "must be greater than zero")
+	check(cfg.GraphQL.MaxComplexity > 0, "graphql.max_complexity", "must be greater than zero")
+	check(cfg.Cache.TTL > 0, "cache.ttl", "must be greater than zero")
+	check(cfg.Cache.StatsInterval > 0, "cache.stats_interval", "must be greater than zero")
+
+	for group, limit := range cfg.Limiter.Groups {
+		check(limit.Requests > 0 && limit.Window >= time.Second, "limiter.groups."+group, "must allow at least one request per window of at least one second")
+	}
+
+	check(cfg.Log.Format == "text" || cfg.Log.Format == "json", "log.format", "must be text or json")
+
+	for _, name := range cfg.Health.Optional {
+		valid := name == "db" || name == "redis" || name == "oidc" || strings.HasPrefix(name, "oidc:")
+		check(valid, "health.optional", fmt.Sprintf("unknown check %q", name))
+	}
+
+	check(slices.Contains([]string{"none", "otlp", "stdout"}, cfg.Tracing.Exporter), "tracing.exporter", "must be none, otlp or stdout")
+	check(cfg.Tracing.Exporter != "otlp" || cfg.Tracing.Endpoint != "", "tracing.endpoint", "must be provided for the otlp exporter")
+	check(cfg.Tracing.SampleRatio >= 0 && cfg.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be between 0 and 1")
+
+	return errors.Join(errs...)
+}
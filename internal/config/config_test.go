@@ -0,0 +1,241 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearEnv blanks every variable Load reads, which it treats as unset, so the
+// tests don't depend on the environment they run in.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, ev := range envVars {
+		t.Setenv(ev.name, "")
+	}
+	for _, name := range []string{"ENV", "CONFIG_FILE", "AUTH0_DOMAIN", "AUTH0_CLIENT_ID", "AUTH0_CLIENT_SECRET", "GOOGLE_CLIENT_ID", "GOOGLE_CLIENT_SECRET"} {
+		t.Setenv(name, "")
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const baseFile = `
+db:
+  dsn: postgres://file
+session:
+  key: file-key
+`
+
+func TestLoadPrecedence(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		env   map[string]string
+		args  []string
+		check func(*Config) bool
+	}{
+		{
+			name:  "defaults",
+			file:  baseFile,
+			check: func(c *Config) bool { return c.Port == 4000 && c.Session.MaxAge == 24*time.Hour },
+		},
+		{
+			name:  "file over defaults",
+			file:  baseFile + "port: 5000\n",
+			check: func(c *Config) bool { return c.Port == 5000 && c.DB.DSN == "postgres://file" },
+		},
+		{
+			name:  "file expands env references",
+			file:  baseFile + "mail:\n  sendgrid_key: ${TEST_SENDGRID}\n",
+			env:   map[string]string{"TEST_SENDGRID": "from-env"},
+			check: func(c *Config) bool { return c.Mail.SendGridKey == "from-env" },
+		},
+		{
+			name:  "bare dollar is left alone",
+			file:  baseFile + "mail:\n  sendgrid_key: a$TEST_SENDGRID\n",
+			env:   map[string]string{"TEST_SENDGRID": "from-env"},
+			check: func(c *Config) bool { return c.Mail.SendGridKey == "a$TEST_SENDGRID" },
+		},
+		{
+			name:  "env over file",
+			file:  baseFile + "port: 5000\n",
+			env:   map[string]string{"PORT": "6000", "DB_URL": "postgres://env"},
+			check: func(c *Config) bool { return c.Port == 6000 && c.DB.DSN == "postgres://env" },
+		},
+		{
+			name:  "flags over env",
+			file:  baseFile + "port: 5000\n",
+			env:   map[string]string{"PORT": "6000"},
+			args:  []string{"-port", "7000"},
+			check: func(c *Config) bool { return c.Port == 7000 },
+		},
+		{
+			name:  "env picks the environment's defaults",
+			file:  baseFile,
+			env:   map[string]string{"ENV": "staging"},
+			check: func(c *Config) bool { return c.Env == "staging" && c.Log.Format == "json" },
+		},
+		{
+			name:  "file overrides the environment's defaults",
+			file:  baseFile + "env: staging\nlog:\n  format: text\n",
+			check: func(c *Config) bool { return c.Env == "staging" && c.Log.Format == "text" },
+		},
+		{
+			name: "legacy provider variables without configured providers",
+			file: baseFile,
+			env:  map[string]string{"AUTH0_DOMAIN": "tenant.auth0.com", "AUTH0_CLIENT_ID": "id", "AUTH0_CLIENT_SECRET": "secret"},
+			check: func(c *Config) bool {
+				return len(c.Auth.Providers) == 1 && c.Auth.Providers[0].Name == "auth0" &&
+					c.Auth.Providers[0].Issuer == "https://tenant.auth0.com/" && c.Auth.Providers[0].Type == ProviderOIDC
+			},
+		},
+		{
+			name: "configured providers win over legacy variables",
+			file: baseFile + "auth:\n  providers:\n    - name: keycloak\n      issuer: https://kc/\n      client_id: id\n      client_secret: secret\n",
+			env:  map[string]string{"GOOGLE_CLIENT_ID": "id", "GOOGLE_CLIENT_SECRET": "secret"},
+			check: func(c *Config) bool {
+				return len(c.Auth.Providers) == 1 && c.Auth.Providers[0].Name == "keycloak"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			args := append([]string{"-config", writeFile(t, "config.yaml", tt.file)}, tt.args...)
+
+			cfg, err := Load(args)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if !tt.check(cfg) {
+				t.Errorf("unexpected config: %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestLoadTOML(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.toml", "port = 5000\n[db]\ndsn = \"postgres://file\"\n[session]\nkey = \"file-key\"\n")
+
+	cfg, err := Load([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Port != 5000 {
+		t.Errorf("port = %d, want 5000", cfg.Port)
+	}
+}
+
+func TestLoadPartialLegacyProvider(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("GOOGLE_CLIENT_ID", "id")
+
+	_, err := Load([]string{"-config", writeFile(t, "config.yaml", baseFile)})
+	if err == nil || !strings.Contains(err.Error(), "auth.providers[0].client_secret") {
+		t.Fatalf("Load error = %v, want a missing client_secret", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() Config {
+		cfg := Defaults("development")
+		cfg.DB.DSN = "postgres://db"
+		cfg.Session.Key = "key"
+		return cfg
+	}
+	provider := func(name string) Provider {
+		p := Provider{Name: name, Issuer: "https://issuer/", ClientID: "id", ClientSecret: "secret"}
+		p.applyDefaults()
+		return p
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   []string
+	}{
+		{name: "valid", modify: func(*Config) {}},
+		{name: "port", modify: func(c *Config) { c.Port = 0 }, want: []string{"port:"}},
+		{name: "env", modify: func(c *Config) { c.Env = "test" }, want: []string{"env:"}},
+		{name: "required values", modify: func(c *Config) { c.DB.DSN, c.Redis.Addr, c.Session.Key = "", "", "" }, want: []string{"db.dsn", "redis.addr", "session.key"}},
+		{
+			name:   "production",
+			modify: func(c *Config) { c.Env = "production" },
+			want:   []string{"session.key: must be at least 32 bytes", "mail.sendgrid_key"},
+		},
+		{name: "idle timeout", modify: func(c *Config) { c.Session.IdleTimeout = time.Second }, want: []string{"session.idle_timeout"}},
+		{name: "remember me", modify: func(c *Config) { c.Session.RememberMeMaxAge = time.Hour }, want: []string{"session.remember_me_max_age"}},
+		{
+			name:   "duplicate provider",
+			modify: func(c *Config) { c.Auth.Providers = []Provider{provider("google"), provider("google")} },
+			want:   []string{`auth.providers[1].name: "google" is declared more than once`},
+		},
+		{
+			name:   "reserved provider",
+			modify: func(c *Config) { c.Auth.Providers = []Provider{provider(PasswordProvider)} },
+			want:   []string{"auth.providers[0].name"},
+		},
+		{
+			name: "incomplete provider",
+			modify: func(c *Config) {
+				c.Auth.Providers = []Provider{{Name: "gitea", Type: ProviderOAuth2}}
+			},
+			want: []string{"client_id", "client_secret", "auth_url", "token_url", "userinfo_url", "claims.subject"},
+		},
+		{
+			name:   "unknown preset",
+			modify: func(c *Config) { p := provider("x"); p.Preset = "nope"; c.Auth.Providers = []Provider{p} },
+			want:   []string{`unknown preset "nope"`},
+		},
+		{name: "admin", modify: func(c *Config) { c.Auth.Admins = []string{"admin"} }, want: []string{"auth.admins[0]"}},
+		{name: "log format", modify: func(c *Config) { c.Log.Format = "xml" }, want: []string{"log.format"}},
+		{name: "health check", modify: func(c *Config) { c.Health.Optional = []string{"disk"} }, want: []string{`unknown check "disk"`}},
+		{
+			name:   "tracing",
+			modify: func(c *Config) { c.Tracing.Exporter, c.Tracing.Endpoint, c.Tracing.SampleRatio = "otlp", "", 2 },
+			want:   []string{"tracing.endpoint", "tracing.sample_ratio"},
+		},
+		{
+			name:   "rate limit",
+			modify: func(c *Config) { c.Limiter.Groups["archive"] = RateLimit{Requests: 1, Window: time.Millisecond} },
+			want:   []string{"limiter.groups.archive"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate succeeded, want an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Provider types.
+const (
+	ProviderOIDC   = "oidc"
+	ProviderOAuth2 = "oauth2"
+)
+
+// Providers recorded for identities that sign in without an identity provider:
+// with an email address and password, or with a link sent to the address. They're
+// reserved, so no configured provider may use them.
+const (
+	PasswordProvider  = "password"
+	MagicLinkProvider = "email"
+)
+
+// defaultOIDCClaims are the standard OpenID Connect claims, which Authentik,
+// Keycloak, Authelia, Auth0 and Google all populate.
+var defaultOIDCClaims = ClaimMapping{
+	Subject:       "sub",
+	Email:         "email",
+	Name:          "name",
+	Username:      "preferred_username",
+	EmailVerified: "email_verified",
+}
+
+// presets describe well-known providers that don't support discovery, so their
+// endpoints don't have to be looked up by everyone who configures them.
+var presets = map[string]Provider{
+	"github": {
+		Type:        ProviderOAuth2,
+		Scopes:      []string{"read:user", "user:email"},
+		AuthURL:     "https://github.com/login/oauth/authorize",
+		TokenURL:    "https://github.com/login/oauth/access_token",
+		UserInfoURL: "https://api.github.com/user",
+		Claims: ClaimMapping{
+			Subject:  "id",
+			Email:    "email",
+			Name:     "name",
+			Username: "login",
+		},
+	},
+}
+
+// legacyProviders returns the Auth0 and Google providers configured through the
+// environment variables the server read before providers could be declared in the
+// config file. A provider is returned once any of its variables is set, so one
+// that's only partly configured fails validation rather than silently vanishing.
+func legacyProviders() []Provider {
+	env := func(name string) string { return strings.TrimSpace(os.Getenv(name)) }
+
+	var providers []Provider
+	if domain, id, secret := env("AUTH0_DOMAIN"), env("AUTH0_CLIENT_ID"), env("AUTH0_CLIENT_SECRET"); domain != "" || id != "" || secret != "" {
+		p := Provider{Name: "auth0", ClientID: id, ClientSecret: secret}
+		if domain != "" {
+			p.Issuer = "https://" + domain + "/"
+			p.EndSessionURL = "https://" + domain + "/oidc/logout"
+		}
+		providers = append(providers, p)
+	}
+	if id, secret := env("GOOGLE_CLIENT_ID"), env("GOOGLE_CLIENT_SECRET"); id != "" || secret != "" {
+		providers = append(providers, Provider{
+			Name:         "google",
+			Issuer:       "https://accounts.google.com",
+			ClientID:     id,
+			ClientSecret: secret,
+		})
+	}
+	return providers
+}
+
+// applyDefaults fills the fields left empty from the provider's preset and, for
+// OIDC providers, the standard claims.
+func (p *Provider) applyDefaults() {
+	if preset, ok := presets[p.Preset]; ok {
+		p.Type = cmp.Or(p.Type, preset.Type)
+		p.AuthURL = cmp.Or(p.AuthURL, preset.AuthURL)
+		p.TokenURL = cmp.Or(p.TokenURL, preset.TokenURL)
+		p.UserInfoURL = cmp.Or(p.UserInfoURL, preset.UserInfoURL)
+		if p.Scopes == nil {
+			p.Scopes = preset.Scopes
+		}
+		p.Claims = p.Claims.merge(preset.Claims)
+	}
+
+	if p.Type == "" {
+		p.Type = ProviderOIDC
+	}
+	if p.Type == ProviderOIDC {
+		p.Claims = p.Claims.merge(defaultOIDCClaims)
+	}
+}
+
+func (p Provider) validate(field string) error {
+	var errs []error
+	check := func(ok bool, name, message string) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s.%s: %s", field, name, message))
+		}
+	}
+
+	if p.Preset != "" {
+		_, ok := presets[p.Preset]
+		check(ok, "preset", fmt.Sprintf("unknown preset %q", p.Preset))
+	}
+	check(p.ClientID != "", "client_id", "must be provided")
+	check(p.ClientSecret != "", "client_secret", "must be provided")
+
+	switch p.Type {
+	case ProviderOIDC:
+		check(p.Issuer != "", "issuer", "must be provided for OIDC providers")
+	case ProviderOAuth2:
+		check(p.AuthURL != "", "auth_url", "must be provided for OAuth2 providers")
+		check(p.TokenURL != "", "token_url", "must be provided for OAuth2 providers")
+		check(p.UserInfoURL != "", "userinfo_url", "must be provided for OAuth2 providers")
+		check(p.Claims.Subject != "", "claims.subject", "must be provided for OAuth2 providers")
+	default:
+		check(false, "type", "must be "+ProviderOIDC+" or "+ProviderOAuth2)
+	}
+	check(!slices.Contains(p.Scopes, ""), "scopes", "must not contain empty scopes")
+
+	return errors.Join(errs...)
+}
+
+// merge returns m with its empty fields taken from defaults.
+func (m ClaimMapping) merge(defaults ClaimMapping) ClaimMapping {
+	return ClaimMapping{
+		Subject:       cmp.Or(m.Subject, defaults.Subject),
+		Email:         cmp.Or(m.Email, defaults.Email),
+		Name:          cmp.Or(m.Name, defaults.Name),
+		Username:      cmp.Or(m.Username, defaults.Username),
+		EmailVerified: cmp.Or(m.EmailVerified, defaults.EmailVerified),
+	}
+}
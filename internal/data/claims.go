@@ -0,0 +1,88 @@
+package data
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/layer8s/home-dashboard-app/internal/validator"
+)
+
+// Statuses of a team claim. A claim starts pending; a commissioner approves or
+// rejects it, and may later revoke an approved one. The claimant can withdraw it
+// while it's pending or approved.
+const (
+	ClaimPending   = "pending"
+	ClaimApproved  = "approved"
+	ClaimRejected  = "rejected"
+	ClaimRevoked   = "revoked"
+	ClaimWithdrawn = "withdrawn"
+)
+
+// OpenClaimStatuses are the statuses of claims still in effect or awaiting a
+// decision. A user can only have one open claim on the same owner or team.
+var OpenClaimStatuses = []string{ClaimPending, ClaimApproved}
+
+// ClaimDecisionFrom returns the statuses a claim can be moved to status from by a
+// commissioner, or nil if they can't set it.
+func ClaimDecisionFrom(status string) []string {
+	switch status {
+	case ClaimApproved, ClaimRejected:
+		return []string{ClaimPending}
+	case ClaimRevoked:
+		return []string{ClaimApproved}
+	default:
+		return nil
+	}
+}
+
+// TeamClaim is a user's request to be recognised as the manager of the teams an
+// owner name, or an ESPN team ID, stands for in a league.
+type TeamClaim struct {
+	ID       int64  `json:"id"`
+	UserID   int64  `json:"user_id"`
+	UserName string `json:"user_name,omitempty"`
+	// LeagueID is the ESPN league ID, shared by every season of the league.
+	LeagueID  int32     `json:"league_id"`
+	Owner     *string   `json:"owner,omitempty"`
+	TeamID    *int32    `json:"team_id,omitempty"`
+	Status    string    `json:"status"`
+	Message   string    `json:"message,omitempty"`
+	DecidedBy *int64    `json:"decided_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Target describes what the claim is for, for display.
+func (c TeamClaim) Target() string {
+	if c.Owner != nil {
+		return *c.Owner
+	}
+	if c.TeamID != nil {
+		return "team " + strconv.Itoa(int(*c.TeamID))
+	}
+	return ""
+}
+
+// TeamClaimEvent is one entry in the history of a claim.
+type TeamClaimEvent struct {
+	Status    string    `json:"status"`
+	ActorID   *int64    `json:"actor_id,omitempty"`
+	ActorName string    `json:"actor_name,omitempty"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ValidateTeamClaim checks a new claim names exactly one of an owner or a team.
+func ValidateTeamClaim(v *validator.Validator, owner string, teamID int32, message string) {
+	v.Check(owner != "" || teamID != 0, "owner", "an owner or a team_id must be provided")
+	v.Check(owner == "" || teamID == 0, "team_id", "must not be provided with an owner")
+	v.Check(len(owner) <= 50, "owner", "must not be more than 50 bytes long")
+	v.Check(teamID >= 0, "team_id", "must be a positive integer")
+	v.Check(len(message) <= 500, "message", "must not be more than 500 bytes long")
+}
+
+// ValidateClaimDecision checks a commissioner's decision on a claim.
+func ValidateClaimDecision(v *validator.Validator, status, note string) {
+	v.Check(ClaimDecisionFrom(status) != nil, "status", "must be approved, rejected or revoked")
+	v.Check(len(note) <= 500, "note", "must not be more than 500 bytes long")
+}
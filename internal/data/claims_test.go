@@ -0,0 +1,25 @@
+package data
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestClaimDecisionFrom(t *testing.T) {
+	tests := []struct {
+		status string
+		want   []string
+	}{
+		{ClaimApproved, []string{ClaimPending}},
+		{ClaimRejected, []string{ClaimPending}},
+		{ClaimRevoked, []string{ClaimApproved}},
+		{ClaimPending, nil},
+		{ClaimWithdrawn, nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := ClaimDecisionFrom(tt.status); !slices.Equal(got, tt.want) {
+			t.Errorf("ClaimDecisionFrom(%q) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
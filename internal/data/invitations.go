@@ -0,0 +1,75 @@
+package data
+
+import (
+	"crypto/sha256"
+	"time"
+
+	"github.com/layer8s/home-dashboard-app/internal/validator"
+)
+
+// ScopeInvitation tokens invite someone to join a league. They're kept with the
+// invitation rather than in the tokens table, as they don't belong to a user yet.
+const ScopeInvitation = "invitation"
+
+// Statuses of an invitation, derived from when it was accepted, revoked or
+// expires.
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationRevoked  = "revoked"
+	InvitationExpired  = "expired"
+)
+
+// Invitation is an emailed invitation to join a league with a role and,
+// optionally, as the manager of an owner's or ESPN team's teams.
+type Invitation struct {
+	ID int64 `json:"id"`
+	// LeagueID is the ESPN league ID, shared by every season of the league.
+	LeagueID   int32      `json:"league_id"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	Owner      *string    `json:"owner,omitempty"`
+	TeamID     *int32     `json:"team_id,omitempty"`
+	InvitedBy  *int64     `json:"invited_by,omitempty"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	AcceptedBy *int64     `json:"accepted_by,omitempty"`
+}
+
+// InvitationStatus works out the status of an invitation.
+func InvitationStatus(acceptedAt, revokedAt *time.Time, expiresAt time.Time) string {
+	switch {
+	case acceptedAt != nil:
+		return InvitationAccepted
+	case revokedAt != nil:
+		return InvitationRevoked
+	case !expiresAt.After(time.Now()):
+		return InvitationExpired
+	default:
+		return InvitationPending
+	}
+}
+
+// NewInvitationToken generates the token for an invitation that expires after
+// ttl.
+func NewInvitationToken(ttl time.Duration) (*Token, error) {
+	return generateToken(0, ttl, ScopeInvitation)
+}
+
+// HashToken returns the hash of a token's plaintext, as stored.
+func HashToken(tokenPlaintext string) []byte {
+	hash := sha256.Sum256([]byte(tokenPlaintext))
+	return hash[:]
+}
+
+func ValidateInvitation(v *validator.Validator, email, role, owner string, teamID int32, expiresInDays int) {
+	ValidateEmail(v, email)
+	ValidateRole(v, role)
+	v.Check(owner == "" || teamID == 0, "team_id", "must not be provided with an owner")
+	v.Check(len(owner) <= 50, "owner", "must not be more than 50 bytes long")
+	v.Check(teamID >= 0, "team_id", "must be a positive integer")
+	v.Check(expiresInDays >= 1, "expires_in_days", "must be at least 1")
+	v.Check(expiresInDays <= 30, "expires_in_days", "must not be more than 30")
+}
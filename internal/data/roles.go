@@ -0,0 +1,68 @@
+package data
+
+import (
+	"time"
+
+	"github.com/layer8s/home-dashboard-app/internal/validator"
+)
+
+// Roles a user can hold in a league, from most to least privileged. Each role can
+// do everything the ones below it can.
+const (
+	// RoleAdmin can do anything in the league, including granting any role.
+	RoleAdmin = "admin"
+	// RoleCommissioner runs the league: they manage its members and viewers and
+	// approve changes made by members.
+	RoleCommissioner = "commissioner"
+	// RoleMember plays in the league.
+	RoleMember = "member"
+	// RoleViewer can see the league's members but change nothing.
+	RoleViewer = "viewer"
+)
+
+// Roles lists every role, most privileged first.
+var Roles = []string{RoleAdmin, RoleCommissioner, RoleMember, RoleViewer}
+
+// LeagueMember is a user holding a role in a league.
+type LeagueMember struct {
+	UserID int64  `json:"user_id"`
+	Name   string `json:"name"`
+	// Email is only shown to commissioners and admins.
+	Email     string    `json:"email,omitempty"`
+	Role      string    `json:"role"`
+	GrantedBy *int64    `json:"granted_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+var roleRanks = map[string]int{
+	RoleViewer:       1,
+	RoleMember:       2,
+	RoleCommissioner: 3,
+	RoleAdmin:        4,
+}
+
+// RoleAtLeast reports whether role grants everything min does. No role, "", is
+// below every role.
+func RoleAtLeast(role, min string) bool {
+	return roleRanks[role] >= roleRanks[min]
+}
+
+// CanAssignRole reports whether someone holding actor may change another user's
+// role in the same league from one role to another. Either may be "", for
+// granting a first role or revoking the last one. Admins may assign anything;
+// commissioners only the roles below their own.
+func CanAssignRole(actor, from, to string) bool {
+	switch actor {
+	case RoleAdmin:
+		return true
+	case RoleCommissioner:
+		return !RoleAtLeast(from, RoleCommissioner) && !RoleAtLeast(to, RoleCommissioner)
+	default:
+		return false
+	}
+}
+
+func ValidateRole(v *validator.Validator, role string) {
+	v.Check(role != "", "role", "must be provided")
+	v.Check(role == "" || validator.PermittedValue(role, Roles...), "role", "must be one of admin, commissioner, member or viewer")
+}
@@ -0,0 +1,49 @@
+package data
+
+import "testing"
+
+func TestRoleAtLeast(t *testing.T) {
+	tests := []struct {
+		role, min string
+		want      bool
+	}{
+		{RoleAdmin, RoleCommissioner, true},
+		{RoleCommissioner, RoleCommissioner, true},
+		{RoleMember, RoleCommissioner, false},
+		{RoleViewer, RoleMember, false},
+		{RoleViewer, "", true},
+		{"", "", true},
+		{"", RoleViewer, false},
+		{"owner", RoleViewer, false},
+	}
+	for _, tt := range tests {
+		if got := RoleAtLeast(tt.role, tt.min); got != tt.want {
+			t.Errorf("RoleAtLeast(%q, %q) = %v, want %v", tt.role, tt.min, got, tt.want)
+		}
+	}
+}
+
+func TestCanAssignRole(t *testing.T) {
+	tests := []struct {
+		actor, from, to string
+		want            bool
+	}{
+		{RoleAdmin, "", RoleAdmin, true},
+		{RoleAdmin, RoleCommissioner, "", true},
+		{RoleCommissioner, "", RoleMember, true},
+		{RoleCommissioner, RoleViewer, RoleMember, true},
+		{RoleCommissioner, RoleMember, "", true},
+		{RoleCommissioner, "", RoleCommissioner, false},
+		{RoleCommissioner, RoleMember, RoleAdmin, false},
+		{RoleCommissioner, RoleCommissioner, RoleMember, false},
+		{RoleCommissioner, RoleAdmin, "", false},
+		{RoleMember, "", RoleViewer, false},
+		{RoleViewer, "", RoleViewer, false},
+		{"", "", RoleViewer, false},
+	}
+	for _, tt := range tests {
+		if got := CanAssignRole(tt.actor, tt.from, tt.to); got != tt.want {
+			t.Errorf("CanAssignRole(%q, %q, %q) = %v, want %v", tt.actor, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package data
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Session is a browser session signed in as a user, as listed on their dashboard.
+type Session struct {
+	// Handle stands for the session in URLs and forms. The session's own ID is as
+	// good as its cookie, so it's never shown.
+	Handle    string
+	UserID    int64
+	Provider  string
+	UserAgent string
+	IP        string
+	CreatedAt time.Time
+	LastSeen  time.Time
+	ExpiresAt time.Time
+	// Remember is set for "keep me signed in" sessions, which last longer and
+	// don't time out when idle.
+	Remember bool
+	// Current is set for the session the list was asked for from.
+	Current bool
+}
+
+// SessionHandle returns the handle of the session with sessionID.
+func SessionHandle(sessionID string) string {
+	hash := sha256.Sum256([]byte(sessionID))
+	return hex.EncodeToString(hash[:8])
+}
+
+// Device describes the browser and operating system in the session's User-Agent,
+// roughly, for display.
+func (s Session) Device() string {
+	ua := s.UserAgent
+	if ua == "" {
+		return "Unknown device"
+	}
+
+	browser := ""
+	for _, b := range []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	} {
+		if strings.Contains(ua, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	os := ""
+	for _, o := range []struct{ token, name string }{
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(ua, o.token) {
+			os = o.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "" || os != "":
+		return browser + os
+	case len(ua) > 60:
+		return ua[:60] + "…"
+	default:
+		return ua
+	}
+}
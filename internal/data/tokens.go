@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/base32"
+	"errors"
 	"time"
 
 	"github.com/layer8s/home-dashboard-app/internal/db"
@@ -12,9 +14,20 @@ import (
 )
 
 const (
-	ScopeActivation = "activation"
+	ScopeActivation    = "activation"
+	ScopePasswordReset = "password-reset"
+	// ScopeAPI tokens authenticate API requests as their user.
+	ScopeAPI = "api"
+	// ScopeAPIReadOnly tokens authenticate API requests as their user, but only
+	// for reading.
+	ScopeAPIReadOnly = "api:read"
 )
 
+// APIScopes are the scopes of personal API tokens.
+var APIScopes = []string{ScopeAPI, ScopeAPIReadOnly}
+
+var ErrTokenNotFound = errors.New("token not found")
+
 type Token struct {
 	Plaintext string
 	Hash      []byte
@@ -35,11 +48,14 @@ func NewTokenService(queries *db.Queries) *TokenModel {
 
 func generateToken(userID int64, ttl time.Duration, scope string) (*Token, error) {
 	// Create a new Token instance with the provided values.
+	// A ttl of zero means the token never expires.
 	token := &Token{
 		UserID: userID,
-		Expiry: time.Now().Add(ttl),
 		Scope:  scope,
 	}
+	if ttl > 0 {
+		token.Expiry = time.Now().Add(ttl)
+	}
 
 	// Initialize a zero-valued byte slice with a length of 16 bytes.
 	randomBytes := make([]byte, 16)
@@ -94,7 +110,7 @@ func (m TokenModel) Insert(ctx context.Context, token *Token) error {
 	params := db.InsertTokenParams{
 		Hash:   token.Hash,
 		UserID: token.UserID,
-		Expiry: token.Expiry,
+		Expiry: nullTime(token.Expiry),
 		Scope:  token.Scope,
 	}
 
@@ -115,3 +131,155 @@ func (m TokenModel) DeleteAllForUser(ctx context.Context, scope string, userID i
 
 	return m.queries.DeleteToken(ctx, params)
 }
+
+// APIToken is a personal API token as its owner sees it. Plaintext is only set
+// in the response that creates the token; it can't be recovered afterwards.
+type APIToken struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Scope      string     `json:"scope"`
+	Expiry     *time.Time `json:"expiry"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	Plaintext  string     `json:"token,omitempty"`
+}
+
+// TokenUser is the user an API token authenticates as.
+type TokenUser struct {
+	UserID  int64
+	Name    string
+	TokenID int64
+	Scope   string
+}
+
+// ValidateAPIToken checks the name and lifetime, in days, asked for a new API
+// token. Zero days means it never expires.
+func ValidateAPIToken(v *validator.Validator, name string, expiresInDays int) {
+	v.Check(name != "", "name", "must be provided")
+	v.Check(len(name) <= 100, "name", "must not be more than 100 bytes long")
+	v.Check(expiresInDays >= 0, "expires_in_days", "must not be negative")
+	v.Check(expiresInDays <= 365, "expires_in_days", "must not be more than 365")
+}
+
+// NewAPIToken creates a personal API token for userID. A ttl of zero means it
+// never expires.
+func (m *TokenModel) NewAPIToken(ctx context.Context, userID int64, name string, ttl time.Duration, scope string) (*APIToken, error) {
+	token, err := generateToken(userID, ttl, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := m.queries.InsertAPIToken(ctx, db.InsertAPITokenParams{
+		Hash:   token.Hash,
+		UserID: userID,
+		Expiry: nullTime(token.Expiry),
+		Scope:  scope,
+		Name:   name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &APIToken{
+		ID:        row.ID,
+		Name:      name,
+		Scope:     scope,
+		Expiry:    timePtr(nullTime(token.Expiry)),
+		CreatedAt: row.CreatedAt,
+		Plaintext: token.Plaintext,
+	}, nil
+}
+
+// ListAPITokens returns userID's personal API tokens, newest first.
+func (m TokenModel) ListAPITokens(ctx context.Context, userID int64) ([]APIToken, error) {
+	rows, err := m.queries.ListAPITokens(ctx, db.ListAPITokensParams{
+		UserID: userID,
+		Scopes: APIScopes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]APIToken, 0, len(rows))
+	for _, row := range rows {
+		tokens = append(tokens, APIToken{
+			ID:         row.ID,
+			Name:       row.Name,
+			Scope:      row.Scope,
+			Expiry:     timePtr(row.Expiry),
+			CreatedAt:  row.CreatedAt,
+			LastUsedAt: timePtr(row.LastUsedAt),
+		})
+	}
+	return tokens, nil
+}
+
+// RevokeAPIToken deletes one of userID's personal API tokens. It returns
+// ErrTokenNotFound if they have no such token.
+func (m TokenModel) RevokeAPIToken(ctx context.Context, userID, id int64) error {
+	n, err := m.queries.DeleteAPIToken(ctx, db.DeleteAPITokenParams{
+		ID:     id,
+		UserID: userID,
+		Scopes: APIScopes,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrTokenNotFound
+	}
+	return nil
+}
+
+// GetUserForAPIToken looks up the user a personal API token belongs to by the
+// token's hash. It returns ErrTokenNotFound for unknown, revoked and expired
+// tokens alike.
+func (m TokenModel) GetUserForAPIToken(ctx context.Context, tokenPlaintext string) (*TokenUser, error) {
+	return m.getUserForToken(ctx, APIScopes, tokenPlaintext)
+}
+
+// GetUserForToken looks up the user a token with the given scope, such as an
+// activation token, belongs to. It returns ErrTokenNotFound for unknown and
+// expired tokens.
+func (m TokenModel) GetUserForToken(ctx context.Context, scope, tokenPlaintext string) (*TokenUser, error) {
+	return m.getUserForToken(ctx, []string{scope}, tokenPlaintext)
+}
+
+func (m TokenModel) getUserForToken(ctx context.Context, scopes []string, tokenPlaintext string) (*TokenUser, error) {
+	hash := sha256.Sum256([]byte(tokenPlaintext))
+
+	row, err := m.queries.GetUserForToken(ctx, db.GetUserForTokenParams{
+		Hash:   hash[:],
+		Scopes: scopes,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenUser{
+		UserID:  row.ID,
+		Name:    row.Name,
+		TokenID: row.TokenID,
+		Scope:   row.Scope,
+	}, nil
+}
+
+// Touch records that a token has just been used. It's only written about once
+// a minute per token, however often the token is used.
+func (m TokenModel) Touch(ctx context.Context, tokenID int64) error {
+	return m.queries.TouchToken(ctx, tokenID)
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
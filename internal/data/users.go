@@ -1,13 +1,17 @@
 package data
 
 import (
+	"errors"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/alexedwards/argon2id"
 	"github.com/layer8s/home-dashboard-app/internal/validator"
 )
 
+var ErrDuplicateEmail = errors.New("duplicate email")
+
 type User struct {
 	ID        int64     `json:"id"`
 	CreatedAt time.Time `json:"created_at"`
@@ -51,6 +55,30 @@ func (p *password) Matches(plaintextPassword string) (bool, error) {
 	return match, nil
 }
 
+// SetHash sets the hash of a password read back from the database, so Matches
+// can check plaintext passwords against it.
+func (p *password) SetHash(hash string) {
+	p.hash = &hash
+}
+
+// dummyHash is a hash of no one's password, made with the same parameters as real
+// ones so checking a password against it takes as long.
+var dummyHash = sync.OnceValues(func() (string, error) {
+	return argon2id.CreateHash("no user has this password", params)
+})
+
+// SetDummyHash sets a hash no password is checked against for real, for when
+// there's no user to check one for. Comparing with it anyway keeps the time a
+// failed sign-in takes from giving away whether the email address is registered.
+func (p *password) SetDummyHash() error {
+	hash, err := dummyHash()
+	if err != nil {
+		return err
+	}
+	p.hash = &hash
+	return nil
+}
+
 func (p *password) Hash() *string {
 	return p.hash
 }
@@ -66,9 +94,14 @@ func ValidatePasswordPlaintext(v *validator.Validator, password string) {
 	v.Check(len(password) <= 72, "password", "must not be more than 72 bytes long")
 }
 
+func ValidateName(v *validator.Validator, name string) {
+	v.Check(name != "", "name", "must be provided")
+	v.Check(len(name) <= 500, "name", "must not be more than 500 bytes long")
+}
+
 func ValidateUser(v *validator.Validator, user *User) {
-	v.Check(user.Name != "", "name", "must be provided")
-	v.Check(len(user.Name) <= 500, "name", "must not be more than 500 bytes long")
+	// Call the standalone ValidateName() helper.
+	ValidateName(v, user.Name)
 
 	// Call the standalone ValidateEmail() helper.
 	ValidateEmail(v, user.Email)
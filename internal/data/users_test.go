@@ -0,0 +1,18 @@
+package data
+
+import "testing"
+
+func TestPasswordDummyHash(t *testing.T) {
+	var p password
+	if err := p.SetDummyHash(); err != nil {
+		t.Fatal(err)
+	}
+	match, err := p.Matches("correct horse battery staple")
+	if err != nil {
+		t.Fatalf("Matches: %v", err)
+	}
+	if match {
+		t.Error("a password matched the dummy hash")
+	}
+
+}
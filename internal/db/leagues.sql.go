@@ -7,6 +7,8 @@ package db
 
 import (
 	"context"
+
+	"github.com/lib/pq"
 )
 
 const getLeagueById = `-- name: GetLeagueById :one
@@ -220,3 +222,39 @@ func (q *Queries) GetLeaguesDesc(ctx context.Context, arg GetLeaguesDescParams)
 	}
 	return items, nil
 }
+
+const listLeaguesByIDs = `-- name: ListLeaguesByIDs :many
+SELECT "id", "leagueId", "year", "teamCount", "currentWeek", "nflWeek"
+FROM "leagues"
+WHERE "id" = ANY($1::int[])
+`
+
+func (q *Queries) ListLeaguesByIDs(ctx context.Context, ids []int32) ([]League, error) {
+	rows, err := q.db.QueryContext(ctx, listLeaguesByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []League
+	for rows.Next() {
+		var i League
+		if err := rows.Scan(
+			&i.ID,
+			&i.LeagueId,
+			&i.Year,
+			&i.TeamCount,
+			&i.CurrentWeek,
+			&i.NflWeek,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
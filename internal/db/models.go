@@ -9,6 +9,35 @@ import (
 	"time"
 )
 
+type Activity struct {
+	ID        int32           `json:"id"`
+	Date      sql.NullInt64   `json:"date"`
+	TeamID    sql.NullInt32   `json:"team_id"`
+	PlayerID  int32           `json:"player_id"`
+	BidAmount sql.NullFloat64 `json:"bidAmount"`
+	Action    sql.NullString  `json:"action"`
+}
+
+type ArchiveRevision struct {
+	ID                bool      `json:"id"`
+	Revision          int64     `json:"revision"`
+	CurrentSeason     int32     `json:"current_season"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	LastImportSeconds float64   `json:"last_import_seconds"`
+}
+
+type Draft struct {
+	ID               int32         `json:"id"`
+	TeamID           int32         `json:"team_id"`
+	PlayerID         int32         `json:"player_id"`
+	OverallPick      int32         `json:"overallPick"`
+	RoundNum         int32         `json:"roundNum"`
+	RoundPick        int32         `json:"roundPick"`
+	KeeperStatus     sql.NullBool  `json:"keeperStatus"`
+	BidAmount        sql.NullInt32 `json:"bidAmount"`
+	NominatingTeamID sql.NullInt32 `json:"nominating_team_id"`
+}
+
 type League struct {
 	ID          int32 `json:"id"`
 	LeagueId    int32 `json:"leagueId"`
@@ -18,6 +47,49 @@ type League struct {
 	NflWeek     int32 `json:"nflWeek"`
 }
 
+type LeagueInvitation struct {
+	ID         int64          `json:"id"`
+	Hash       []byte         `json:"hash"`
+	LeagueID   int32          `json:"league_id"`
+	Email      string         `json:"email"`
+	Role       string         `json:"role"`
+	Owner      sql.NullString `json:"owner"`
+	TeamID     sql.NullInt32  `json:"team_id"`
+	InvitedBy  sql.NullInt64  `json:"invited_by"`
+	CreatedAt  time.Time      `json:"created_at"`
+	ExpiresAt  time.Time      `json:"expires_at"`
+	AcceptedAt sql.NullTime   `json:"accepted_at"`
+	AcceptedBy sql.NullInt64  `json:"accepted_by"`
+	RevokedAt  sql.NullTime   `json:"revoked_at"`
+}
+
+type LeagueRole struct {
+	UserID    int64         `json:"user_id"`
+	LeagueID  int32         `json:"league_id"`
+	Role      string        `json:"role"`
+	GrantedBy sql.NullInt64 `json:"granted_by"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+type Matchup struct {
+	ID          int32           `json:"id"`
+	Week        int32           `json:"week"`
+	HomeTeamID  sql.NullInt32   `json:"home_team_id"`
+	AwayTeamID  sql.NullInt32   `json:"away_team_id"`
+	HomeScore   sql.NullFloat64 `json:"homeScore"`
+	AwayScore   sql.NullFloat64 `json:"awayScore"`
+	IsPlayoff   sql.NullBool    `json:"isPlayoff"`
+	MatchupType sql.NullString  `json:"matchupType"`
+}
+
+type Player struct {
+	ID       int32          `json:"id"`
+	EspnId   int32          `json:"espnId"`
+	Name     string         `json:"name"`
+	Position sql.NullString `json:"position"`
+}
+
 type Team struct {
 	ID                     int32          `json:"id"`
 	LeagueID               int32          `json:"league_id"`
@@ -47,20 +119,56 @@ type Team struct {
 	LogoUrl                sql.NullString `json:"logoUrl"`
 }
 
+type TeamClaim struct {
+	ID        int64          `json:"id"`
+	UserID    int64          `json:"user_id"`
+	LeagueID  int32          `json:"league_id"`
+	Owner     sql.NullString `json:"owner"`
+	TeamID    sql.NullInt32  `json:"team_id"`
+	Status    string         `json:"status"`
+	Message   string         `json:"message"`
+	DecidedBy sql.NullInt64  `json:"decided_by"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+type TeamClaimEvent struct {
+	ID        int64         `json:"id"`
+	ClaimID   int64         `json:"claim_id"`
+	Status    string        `json:"status"`
+	ActorID   sql.NullInt64 `json:"actor_id"`
+	Note      string        `json:"note"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
 type Token struct {
-	Hash   []byte    `json:"hash"`
-	UserID int64     `json:"user_id"`
-	Expiry time.Time `json:"expiry"`
-	Scope  string    `json:"scope"`
+	Hash       []byte       `json:"hash"`
+	UserID     int64        `json:"user_id"`
+	Expiry     sql.NullTime `json:"expiry"`
+	Scope      string       `json:"scope"`
+	ID         int64        `json:"id"`
+	Name       string       `json:"name"`
+	CreatedAt  time.Time    `json:"created_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
 }
 
 type User struct {
-	ID         int64          `json:"id"`
-	CreatedAt  time.Time      `json:"created_at"`
-	Name       string         `json:"name"`
-	Email      sql.NullString `json:"email"`
-	Activated  bool           `json:"activated"`
-	Provider   string         `json:"provider"`
-	ProviderID string         `json:"provider_id"`
-	Version    int32          `json:"version"`
+	ID           int64          `json:"id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	Name         string         `json:"name"`
+	Email        sql.NullString `json:"email"`
+	Activated    bool           `json:"activated"`
+	Version      int32          `json:"version"`
+	PasswordHash []byte         `json:"password_hash"`
+}
+
+type UserIdentity struct {
+	ID            int64          `json:"id"`
+	UserID        int64          `json:"user_id"`
+	Provider      string         `json:"provider"`
+	ProviderID    string         `json:"provider_id"`
+	Email         sql.NullString `json:"email"`
+	EmailVerified bool           `json:"email_verified"`
+	CreatedAt     time.Time      `json:"created_at"`
+	LastLoginAt   time.Time      `json:"last_login_at"`
 }
@@ -0,0 +1,68 @@
+package db
+
+// This file is maintained by hand. It reuses the sqlc-generated query strings to
+// provide row-at-a-time variants of :many queries, so large listings can be written
+// to the client as they are read instead of being collected into a slice first.
+
+import (
+	"context"
+)
+
+// EachLeagueAsc runs the GetLeaguesAsc query and calls fn for every row. Iteration
+// stops at the first error returned by fn.
+func (q *Queries) EachLeagueAsc(ctx context.Context, arg GetLeaguesAscParams, fn func(League) error) error {
+	return q.eachLeague(ctx, getLeaguesAsc, fn,
+		arg.ID,
+		arg.LeagueId,
+		arg.Year,
+		arg.TeamCount,
+		arg.CurrentWeek,
+		arg.NflWeek,
+		arg.Limit,
+		arg.Offset,
+		arg.Column9,
+	)
+}
+
+// EachLeagueDesc is the descending counterpart of EachLeagueAsc.
+func (q *Queries) EachLeagueDesc(ctx context.Context, arg GetLeaguesDescParams, fn func(League) error) error {
+	return q.eachLeague(ctx, getLeaguesDesc, fn,
+		arg.ID,
+		arg.LeagueId,
+		arg.Year,
+		arg.TeamCount,
+		arg.CurrentWeek,
+		arg.NflWeek,
+		arg.Limit,
+		arg.Offset,
+		arg.Column9,
+	)
+}
+
+func (q *Queries) eachLeague(ctx context.Context, query string, fn func(League) error, args ...interface{}) error {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var i League
+		if err := rows.Scan(
+			&i.ID,
+			&i.LeagueId,
+			&i.Year,
+			&i.TeamCount,
+			&i.CurrentWeek,
+			&i.NflWeek,
+		); err != nil {
+			return err
+		}
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	return rows.Err()
+}
@@ -8,10 +8,12 @@ package db
 import (
 	"context"
 	"database/sql"
+
+	"github.com/lib/pq"
 )
 
 const getTeamById = `-- name: GetTeamById :one
-SELECT "id", "league_id", "year", "teamAbbrv", "owners", "divisionId", "divisionName", "wins", "losses", "ties", "pointsFor", "pointsAgainst", "waiverRank", "acquisitions", "acquisitionBudgetSpent", "drops", "trades", "logoUrl" 
+SELECT "id", "league_id", "teamId", "year", "teamAbbrv", "teamName", "owners", "divisionId", "divisionName", "wins", "losses", "ties", "pointsFor", "pointsAgainst", "waiverRank", "acquisitions", "acquisitionBudgetSpent", "drops", "trades", "logoUrl" 
 FROM "teams" 
 WHERE "id" = $1
 `
@@ -19,8 +21,10 @@ WHERE "id" = $1
 type GetTeamByIdRow struct {
 	ID                     int32          `json:"id"`
 	LeagueID               int32          `json:"league_id"`
+	TeamId                 int32          `json:"teamId"`
 	Year                   int32          `json:"year"`
 	TeamAbbrv              string         `json:"teamAbbrv"`
+	TeamName               string         `json:"teamName"`
 	Owners                 sql.NullString `json:"owners"`
 	DivisionId             sql.NullString `json:"divisionId"`
 	DivisionName           sql.NullString `json:"divisionName"`
@@ -43,8 +47,10 @@ func (q *Queries) GetTeamById(ctx context.Context, id int32) (GetTeamByIdRow, er
 	err := row.Scan(
 		&i.ID,
 		&i.LeagueID,
+		&i.TeamId,
 		&i.Year,
 		&i.TeamAbbrv,
+		&i.TeamName,
 		&i.Owners,
 		&i.DivisionId,
 		&i.DivisionName,
@@ -62,3 +68,203 @@ func (q *Queries) GetTeamById(ctx context.Context, id int32) (GetTeamByIdRow, er
 	)
 	return i, err
 }
+
+const listOwners = `-- name: ListOwners :many
+SELECT DISTINCT "owners"
+FROM "teams"
+WHERE "owners" IS NOT NULL
+ORDER BY "owners"
+`
+
+func (q *Queries) ListOwners(ctx context.Context) ([]sql.NullString, error) {
+	rows, err := q.db.QueryContext(ctx, listOwners)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullString
+	for rows.Next() {
+		var owners sql.NullString
+		if err := rows.Scan(&owners); err != nil {
+			return nil, err
+		}
+		items = append(items, owners)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTeamsByIDs = `-- name: ListTeamsByIDs :many
+SELECT "id", "league_id", "teamId", "year", "teamAbbrv", "teamName", "owners", "divisionId", "divisionName", "wins", "losses", "ties", "pointsFor", "pointsAgainst", "waiverRank", "acquisitions", "acquisitionBudgetSpent", "drops", "trades", "streakType", "streakLength", "standing", "finalStanding", "draftProjRank", "playoffPct", "logoUrl"
+FROM "teams"
+WHERE "id" = ANY($1::int[])
+`
+
+func (q *Queries) ListTeamsByIDs(ctx context.Context, ids []int32) ([]Team, error) {
+	rows, err := q.db.QueryContext(ctx, listTeamsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Team
+	for rows.Next() {
+		var i Team
+		if err := rows.Scan(
+			&i.ID,
+			&i.LeagueID,
+			&i.TeamId,
+			&i.Year,
+			&i.TeamAbbrv,
+			&i.TeamName,
+			&i.Owners,
+			&i.DivisionId,
+			&i.DivisionName,
+			&i.Wins,
+			&i.Losses,
+			&i.Ties,
+			&i.PointsFor,
+			&i.PointsAgainst,
+			&i.WaiverRank,
+			&i.Acquisitions,
+			&i.AcquisitionBudgetSpent,
+			&i.Drops,
+			&i.Trades,
+			&i.StreakType,
+			&i.StreakLength,
+			&i.Standing,
+			&i.FinalStanding,
+			&i.DraftProjRank,
+			&i.PlayoffPct,
+			&i.LogoUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTeamsByLeagueIDs = `-- name: ListTeamsByLeagueIDs :many
+SELECT "id", "league_id", "teamId", "year", "teamAbbrv", "teamName", "owners", "divisionId", "divisionName", "wins", "losses", "ties", "pointsFor", "pointsAgainst", "waiverRank", "acquisitions", "acquisitionBudgetSpent", "drops", "trades", "streakType", "streakLength", "standing", "finalStanding", "draftProjRank", "playoffPct", "logoUrl"
+FROM "teams"
+WHERE "league_id" = ANY($1::int[])
+ORDER BY "league_id", "teamId"
+`
+
+func (q *Queries) ListTeamsByLeagueIDs(ctx context.Context, leagueIds []int32) ([]Team, error) {
+	rows, err := q.db.QueryContext(ctx, listTeamsByLeagueIDs, pq.Array(leagueIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Team
+	for rows.Next() {
+		var i Team
+		if err := rows.Scan(
+			&i.ID,
+			&i.LeagueID,
+			&i.TeamId,
+			&i.Year,
+			&i.TeamAbbrv,
+			&i.TeamName,
+			&i.Owners,
+			&i.DivisionId,
+			&i.DivisionName,
+			&i.Wins,
+			&i.Losses,
+			&i.Ties,
+			&i.PointsFor,
+			&i.PointsAgainst,
+			&i.WaiverRank,
+			&i.Acquisitions,
+			&i.AcquisitionBudgetSpent,
+			&i.Drops,
+			&i.Trades,
+			&i.StreakType,
+			&i.StreakLength,
+			&i.Standing,
+			&i.FinalStanding,
+			&i.DraftProjRank,
+			&i.PlayoffPct,
+			&i.LogoUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTeamsByOwners = `-- name: ListTeamsByOwners :many
+SELECT "id", "league_id", "teamId", "year", "teamAbbrv", "teamName", "owners", "divisionId", "divisionName", "wins", "losses", "ties", "pointsFor", "pointsAgainst", "waiverRank", "acquisitions", "acquisitionBudgetSpent", "drops", "trades", "streakType", "streakLength", "standing", "finalStanding", "draftProjRank", "playoffPct", "logoUrl"
+FROM "teams"
+WHERE "owners" = ANY($1::text[])
+ORDER BY "year", "teamId"
+`
+
+func (q *Queries) ListTeamsByOwners(ctx context.Context, owners []string) ([]Team, error) {
+	rows, err := q.db.QueryContext(ctx, listTeamsByOwners, pq.Array(owners))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Team
+	for rows.Next() {
+		var i Team
+		if err := rows.Scan(
+			&i.ID,
+			&i.LeagueID,
+			&i.TeamId,
+			&i.Year,
+			&i.TeamAbbrv,
+			&i.TeamName,
+			&i.Owners,
+			&i.DivisionId,
+			&i.DivisionName,
+			&i.Wins,
+			&i.Losses,
+			&i.Ties,
+			&i.PointsFor,
+			&i.PointsAgainst,
+			&i.WaiverRank,
+			&i.Acquisitions,
+			&i.AcquisitionBudgetSpent,
+			&i.Drops,
+			&i.Trades,
+			&i.StreakType,
+			&i.StreakLength,
+			&i.Standing,
+			&i.FinalStanding,
+			&i.DraftProjRank,
+			&i.PlayoffPct,
+			&i.LogoUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -7,9 +7,31 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
 )
 
+const deleteAPIToken = `-- name: DeleteAPIToken :execrows
+DELETE FROM tokens
+WHERE id = $1 AND user_id = $2 AND scope = ANY($3::text[])
+`
+
+type DeleteAPITokenParams struct {
+	ID     int64    `json:"id"`
+	UserID int64    `json:"user_id"`
+	Scopes []string `json:"scopes"`
+}
+
+func (q *Queries) DeleteAPIToken(ctx context.Context, arg DeleteAPITokenParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAPIToken, arg.ID, arg.UserID, pq.Array(arg.Scopes))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteToken = `-- name: DeleteToken :exec
 DELETE FROM tokens 
 WHERE scope = $1 AND user_id = $2
@@ -25,16 +47,81 @@ func (q *Queries) DeleteToken(ctx context.Context, arg DeleteTokenParams) error
 	return err
 }
 
+const getUserForToken = `-- name: GetUserForToken :one
+SELECT users.id, users.name, tokens.id AS token_id, tokens.scope
+FROM users
+JOIN tokens ON tokens.user_id = users.id
+WHERE tokens.hash = $1
+    AND tokens.scope = ANY($2::text[])
+    AND (tokens.expiry IS NULL OR tokens.expiry > NOW())
+`
+
+type GetUserForTokenParams struct {
+	Hash   []byte   `json:"hash"`
+	Scopes []string `json:"scopes"`
+}
+
+type GetUserForTokenRow struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	TokenID int64  `json:"token_id"`
+	Scope   string `json:"scope"`
+}
+
+func (q *Queries) GetUserForToken(ctx context.Context, arg GetUserForTokenParams) (GetUserForTokenRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserForToken, arg.Hash, pq.Array(arg.Scopes))
+	var i GetUserForTokenRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.TokenID,
+		&i.Scope,
+	)
+	return i, err
+}
+
+const insertAPIToken = `-- name: InsertAPIToken :one
+INSERT INTO tokens (hash, user_id, expiry, scope, name)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, created_at
+`
+
+type InsertAPITokenParams struct {
+	Hash   []byte       `json:"hash"`
+	UserID int64        `json:"user_id"`
+	Expiry sql.NullTime `json:"expiry"`
+	Scope  string       `json:"scope"`
+	Name   string       `json:"name"`
+}
+
+type InsertAPITokenRow struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) InsertAPIToken(ctx context.Context, arg InsertAPITokenParams) (InsertAPITokenRow, error) {
+	row := q.db.QueryRowContext(ctx, insertAPIToken,
+		arg.Hash,
+		arg.UserID,
+		arg.Expiry,
+		arg.Scope,
+		arg.Name,
+	)
+	var i InsertAPITokenRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return i, err
+}
+
 const insertToken = `-- name: InsertToken :exec
 INSERT INTO tokens (hash, user_id, expiry, scope) 
 VALUES ($1, $2, $3, $4)
 `
 
 type InsertTokenParams struct {
-	Hash   []byte    `json:"hash"`
-	UserID int64     `json:"user_id"`
-	Expiry time.Time `json:"expiry"`
-	Scope  string    `json:"scope"`
+	Hash   []byte       `json:"hash"`
+	UserID int64        `json:"user_id"`
+	Expiry sql.NullTime `json:"expiry"`
+	Scope  string       `json:"scope"`
 }
 
 func (q *Queries) InsertToken(ctx context.Context, arg InsertTokenParams) error {
@@ -46,3 +133,65 @@ func (q *Queries) InsertToken(ctx context.Context, arg InsertTokenParams) error
 	)
 	return err
 }
+
+const listAPITokens = `-- name: ListAPITokens :many
+SELECT id, name, scope, expiry, created_at, last_used_at
+FROM tokens
+WHERE user_id = $1 AND scope = ANY($2::text[])
+ORDER BY created_at DESC, id DESC
+`
+
+type ListAPITokensParams struct {
+	UserID int64    `json:"user_id"`
+	Scopes []string `json:"scopes"`
+}
+
+type ListAPITokensRow struct {
+	ID         int64        `json:"id"`
+	Name       string       `json:"name"`
+	Scope      string       `json:"scope"`
+	Expiry     sql.NullTime `json:"expiry"`
+	CreatedAt  time.Time    `json:"created_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+}
+
+func (q *Queries) ListAPITokens(ctx context.Context, arg ListAPITokensParams) ([]ListAPITokensRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAPITokens, arg.UserID, pq.Array(arg.Scopes))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAPITokensRow
+	for rows.Next() {
+		var i ListAPITokensRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Scope,
+			&i.Expiry,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchToken = `-- name: TouchToken :exec
+UPDATE tokens
+SET last_used_at = NOW()
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+`
+
+func (q *Queries) TouchToken(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, touchToken, id)
+	return err
+}
@@ -11,10 +11,103 @@ import (
 	"time"
 )
 
+const activateUser = `-- name: ActivateUser :one
+UPDATE users
+SET activated = true, version = version + 1
+WHERE id = $1
+RETURNING id, created_at, name, email, activated, version
+`
+
+type ActivateUserRow struct {
+	ID        int64          `json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	Name      string         `json:"name"`
+	Email     sql.NullString `json:"email"`
+	Activated bool           `json:"activated"`
+	Version   int32          `json:"version"`
+}
+
+func (q *Queries) ActivateUser(ctx context.Context, id int64) (ActivateUserRow, error) {
+	row := q.db.QueryRowContext(ctx, activateUser, id)
+	var i ActivateUserRow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.Name,
+		&i.Email,
+		&i.Activated,
+		&i.Version,
+	)
+	return i, err
+}
+
+const createUser = `-- name: CreateUser :one
+INSERT INTO users (
+    name,
+    email,
+    activated
+)
+VALUES (
+    $1, $2, $3
+)
+RETURNING id, created_at, version
+`
+
+type CreateUserParams struct {
+	Name      string         `json:"name"`
+	Email     sql.NullString `json:"email"`
+	Activated bool           `json:"activated"`
+}
+
+type CreateUserRow struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Version   int32     `json:"version"`
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateUserRow, error) {
+	row := q.db.QueryRowContext(ctx, createUser, arg.Name, arg.Email, arg.Activated)
+	var i CreateUserRow
+	err := row.Scan(&i.ID, &i.CreatedAt, &i.Version)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, created_at, name, email, password_hash, activated, version
+FROM users
+WHERE lower(email) = lower($1) AND password_hash IS NOT NULL
+`
+
+type GetUserByEmailRow struct {
+	ID           int64          `json:"id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	Name         string         `json:"name"`
+	Email        sql.NullString `json:"email"`
+	PasswordHash []byte         `json:"password_hash"`
+	Activated    bool           `json:"activated"`
+	Version      int32          `json:"version"`
+}
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email sql.NullString) (GetUserByEmailRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i GetUserByEmailRow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Activated,
+		&i.Version,
+	)
+	return i, err
+}
+
 const getUserByProvider = `-- name: GetUserByProvider :one
-SELECT id, name
-FROM users 
-WHERE provider = $1 AND provider_id = $2
+SELECT users.id, users.name
+FROM users
+JOIN user_identities ON user_identities.user_id = users.id
+WHERE user_identities.provider = $1 AND user_identities.provider_id = $2
 `
 
 type GetUserByProviderParams struct {
@@ -34,50 +127,56 @@ func (q *Queries) GetUserByProvider(ctx context.Context, arg GetUserByProviderPa
 	return i, err
 }
 
-const upsertUser = `-- name: UpsertUser :one
+const insertUser = `-- name: InsertUser :one
 INSERT INTO users (
     name,
     email,
-    activated,
-    provider,
-    provider_id
+    password_hash,
+    activated
 )
 VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4
 )
-ON CONFLICT (provider, provider_id) DO UPDATE
-SET
-    name = EXCLUDED.name,
-    email = EXCLUDED.email,
-    activated = EXCLUDED.activated,
-    version = users.version + 1,
-    created_at = NOW()
 RETURNING id, created_at, version
 `
 
-type UpsertUserParams struct {
-	Name       string         `json:"name"`
-	Email      sql.NullString `json:"email"`
-	Activated  bool           `json:"activated"`
-	Provider   string         `json:"provider"`
-	ProviderID string         `json:"provider_id"`
+type InsertUserParams struct {
+	Name         string         `json:"name"`
+	Email        sql.NullString `json:"email"`
+	PasswordHash []byte         `json:"password_hash"`
+	Activated    bool           `json:"activated"`
 }
 
-type UpsertUserRow struct {
+type InsertUserRow struct {
 	ID        int64     `json:"id"`
 	CreatedAt time.Time `json:"created_at"`
 	Version   int32     `json:"version"`
 }
 
-func (q *Queries) UpsertUser(ctx context.Context, arg UpsertUserParams) (UpsertUserRow, error) {
-	row := q.db.QueryRowContext(ctx, upsertUser,
+func (q *Queries) InsertUser(ctx context.Context, arg InsertUserParams) (InsertUserRow, error) {
+	row := q.db.QueryRowContext(ctx, insertUser,
 		arg.Name,
 		arg.Email,
+		arg.PasswordHash,
 		arg.Activated,
-		arg.Provider,
-		arg.ProviderID,
 	)
-	var i UpsertUserRow
+	var i InsertUserRow
 	err := row.Scan(&i.ID, &i.CreatedAt, &i.Version)
 	return i, err
 }
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users
+SET password_hash = $1, version = version + 1
+WHERE id = $2
+`
+
+type UpdateUserPasswordParams struct {
+	PasswordHash []byte `json:"password_hash"`
+	ID           int64  `json:"id"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserPassword, arg.PasswordHash, arg.ID)
+	return err
+}
@@ -0,0 +1,1235 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        (unknown)
+// source: archive/v1/archive.proto
+
+package archivev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type League struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	LeagueId      int32                  `protobuf:"varint,2,opt,name=league_id,json=leagueId,proto3" json:"league_id,omitempty"`
+	Year          int32                  `protobuf:"varint,3,opt,name=year,proto3" json:"year,omitempty"`
+	TeamCount     int32                  `protobuf:"varint,4,opt,name=team_count,json=teamCount,proto3" json:"team_count,omitempty"`
+	CurrentWeek   int32                  `protobuf:"varint,5,opt,name=current_week,json=currentWeek,proto3" json:"current_week,omitempty"`
+	NflWeek       int32                  `protobuf:"varint,6,opt,name=nfl_week,json=nflWeek,proto3" json:"nfl_week,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *League) Reset() {
+	*x = League{}
+	mi := &file_archive_v1_archive_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *League) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*League) ProtoMessage() {}
+
+func (x *League) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_v1_archive_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use League.ProtoReflect.Descriptor instead.
+func (*League) Descriptor() ([]byte, []int) {
+	return file_archive_v1_archive_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *League) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *League) GetLeagueId() int32 {
+	if x != nil {
+		return x.LeagueId
+	}
+	return 0
+}
+
+func (x *League) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *League) GetTeamCount() int32 {
+	if x != nil {
+		return x.TeamCount
+	}
+	return 0
+}
+
+func (x *League) GetCurrentWeek() int32 {
+	if x != nil {
+		return x.CurrentWeek
+	}
+	return 0
+}
+
+func (x *League) GetNflWeek() int32 {
+	if x != nil {
+		return x.NflWeek
+	}
+	return 0
+}
+
+type Team struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Id                     int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	LeagueId               int32                  `protobuf:"varint,2,opt,name=league_id,json=leagueId,proto3" json:"league_id,omitempty"`
+	TeamId                 int32                  `protobuf:"varint,3,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	Year                   int32                  `protobuf:"varint,4,opt,name=year,proto3" json:"year,omitempty"`
+	TeamAbbrv              string                 `protobuf:"bytes,5,opt,name=team_abbrv,json=teamAbbrv,proto3" json:"team_abbrv,omitempty"`
+	TeamName               string                 `protobuf:"bytes,6,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	Owners                 *string                `protobuf:"bytes,7,opt,name=owners,proto3,oneof" json:"owners,omitempty"`
+	DivisionId             *string                `protobuf:"bytes,8,opt,name=division_id,json=divisionId,proto3,oneof" json:"division_id,omitempty"`
+	DivisionName           *string                `protobuf:"bytes,9,opt,name=division_name,json=divisionName,proto3,oneof" json:"division_name,omitempty"`
+	Wins                   *int32                 `protobuf:"varint,10,opt,name=wins,proto3,oneof" json:"wins,omitempty"`
+	Losses                 *int32                 `protobuf:"varint,11,opt,name=losses,proto3,oneof" json:"losses,omitempty"`
+	Ties                   *int32                 `protobuf:"varint,12,opt,name=ties,proto3,oneof" json:"ties,omitempty"`
+	PointsFor              *int32                 `protobuf:"varint,13,opt,name=points_for,json=pointsFor,proto3,oneof" json:"points_for,omitempty"`
+	PointsAgainst          *int32                 `protobuf:"varint,14,opt,name=points_against,json=pointsAgainst,proto3,oneof" json:"points_against,omitempty"`
+	WaiverRank             *int32                 `protobuf:"varint,15,opt,name=waiver_rank,json=waiverRank,proto3,oneof" json:"waiver_rank,omitempty"`
+	Acquisitions           *int32                 `protobuf:"varint,16,opt,name=acquisitions,proto3,oneof" json:"acquisitions,omitempty"`
+	AcquisitionBudgetSpent *int32                 `protobuf:"varint,17,opt,name=acquisition_budget_spent,json=acquisitionBudgetSpent,proto3,oneof" json:"acquisition_budget_spent,omitempty"`
+	Drops                  *int32                 `protobuf:"varint,18,opt,name=drops,proto3,oneof" json:"drops,omitempty"`
+	Trades                 *int32                 `protobuf:"varint,19,opt,name=trades,proto3,oneof" json:"trades,omitempty"`
+	StreakType             *string                `protobuf:"bytes,20,opt,name=streak_type,json=streakType,proto3,oneof" json:"streak_type,omitempty"`
+	StreakLength           *int32                 `protobuf:"varint,21,opt,name=streak_length,json=streakLength,proto3,oneof" json:"streak_length,omitempty"`
+	Standing               *int32                 `protobuf:"varint,22,opt,name=standing,proto3,oneof" json:"standing,omitempty"`
+	FinalStanding          *int32                 `protobuf:"varint,23,opt,name=final_standing,json=finalStanding,proto3,oneof" json:"final_standing,omitempty"`
+	DraftProjRank          *int32                 `protobuf:"varint,24,opt,name=draft_proj_rank,json=draftProjRank,proto3,oneof" json:"draft_proj_rank,omitempty"`
+	PlayoffPct             *int32                 `protobuf:"varint,25,opt,name=playoff_pct,json=playoffPct,proto3,oneof" json:"playoff_pct,omitempty"`
+	LogoUrl                *string                `protobuf:"bytes,26,opt,name=logo_url,json=logoUrl,proto3,oneof" json:"logo_url,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *Team) Reset() {
+	*x = Team{}
+	mi := &file_archive_v1_archive_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Team) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Team) ProtoMessage() {}
+
+func (x *Team) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_v1_archive_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Team.ProtoReflect.Descriptor instead.
+func (*Team) Descriptor() ([]byte, []int) {
+	return file_archive_v1_archive_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Team) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Team) GetLeagueId() int32 {
+	if x != nil {
+		return x.LeagueId
+	}
+	return 0
+}
+
+func (x *Team) GetTeamId() int32 {
+	if x != nil {
+		return x.TeamId
+	}
+	return 0
+}
+
+func (x *Team) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *Team) GetTeamAbbrv() string {
+	if x != nil {
+		return x.TeamAbbrv
+	}
+	return ""
+}
+
+func (x *Team) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *Team) GetOwners() string {
+	if x != nil && x.Owners != nil {
+		return *x.Owners
+	}
+	return ""
+}
+
+func (x *Team) GetDivisionId() string {
+	if x != nil && x.DivisionId != nil {
+		return *x.DivisionId
+	}
+	return ""
+}
+
+func (x *Team) GetDivisionName() string {
+	if x != nil && x.DivisionName != nil {
+		return *x.DivisionName
+	}
+	return ""
+}
+
+func (x *Team) GetWins() int32 {
+	if x != nil && x.Wins != nil {
+		return *x.Wins
+	}
+	return 0
+}
+
+func (x *Team) GetLosses() int32 {
+	if x != nil && x.Losses != nil {
+		return *x.Losses
+	}
+	return 0
+}
+
+func (x *Team) GetTies() int32 {
+	if x != nil && x.Ties != nil {
+		return *x.Ties
+	}
+	return 0
+}
+
+func (x *Team) GetPointsFor() int32 {
+	if x != nil && x.PointsFor != nil {
+		return *x.PointsFor
+	}
+	return 0
+}
+
+func (x *Team) GetPointsAgainst() int32 {
+	if x != nil && x.PointsAgainst != nil {
+		return *x.PointsAgainst
+	}
+	return 0
+}
+
+func (x *Team) GetWaiverRank() int32 {
+	if x != nil && x.WaiverRank != nil {
+		return *x.WaiverRank
+	}
+	return 0
+}
+
+func (x *Team) GetAcquisitions() int32 {
+	if x != nil && x.Acquisitions != nil {
+		return *x.Acquisitions
+	}
+	return 0
+}
+
+func (x *Team) GetAcquisitionBudgetSpent() int32 {
+	if x != nil && x.AcquisitionBudgetSpent != nil {
+		return *x.AcquisitionBudgetSpent
+	}
+	return 0
+}
+
+func (x *Team) GetDrops() int32 {
+	if x != nil && x.Drops != nil {
+		return *x.Drops
+	}
+	return 0
+}
+
+func (x *Team) GetTrades() int32 {
+	if x != nil && x.Trades != nil {
+		return *x.Trades
+	}
+	return 0
+}
+
+func (x *Team) GetStreakType() string {
+	if x != nil && x.StreakType != nil {
+		return *x.StreakType
+	}
+	return ""
+}
+
+func (x *Team) GetStreakLength() int32 {
+	if x != nil && x.StreakLength != nil {
+		return *x.StreakLength
+	}
+	return 0
+}
+
+func (x *Team) GetStanding() int32 {
+	if x != nil && x.Standing != nil {
+		return *x.Standing
+	}
+	return 0
+}
+
+func (x *Team) GetFinalStanding() int32 {
+	if x != nil && x.FinalStanding != nil {
+		return *x.FinalStanding
+	}
+	return 0
+}
+
+func (x *Team) GetDraftProjRank() int32 {
+	if x != nil && x.DraftProjRank != nil {
+		return *x.DraftProjRank
+	}
+	return 0
+}
+
+func (x *Team) GetPlayoffPct() int32 {
+	if x != nil && x.PlayoffPct != nil {
+		return *x.PlayoffPct
+	}
+	return 0
+}
+
+func (x *Team) GetLogoUrl() string {
+	if x != nil && x.LogoUrl != nil {
+		return *x.LogoUrl
+	}
+	return ""
+}
+
+type Matchup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Week          int32                  `protobuf:"varint,2,opt,name=week,proto3" json:"week,omitempty"`
+	HomeTeamId    *int32                 `protobuf:"varint,3,opt,name=home_team_id,json=homeTeamId,proto3,oneof" json:"home_team_id,omitempty"`
+	AwayTeamId    *int32                 `protobuf:"varint,4,opt,name=away_team_id,json=awayTeamId,proto3,oneof" json:"away_team_id,omitempty"`
+	HomeScore     *float64               `protobuf:"fixed64,5,opt,name=home_score,json=homeScore,proto3,oneof" json:"home_score,omitempty"`
+	AwayScore     *float64               `protobuf:"fixed64,6,opt,name=away_score,json=awayScore,proto3,oneof" json:"away_score,omitempty"`
+	IsPlayoff     *bool                  `protobuf:"varint,7,opt,name=is_playoff,json=isPlayoff,proto3,oneof" json:"is_playoff,omitempty"`
+	MatchupType   *string                `protobuf:"bytes,8,opt,name=matchup_type,json=matchupType,proto3,oneof" json:"matchup_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Matchup) Reset() {
+	*x = Matchup{}
+	mi := &file_archive_v1_archive_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Matchup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Matchup) ProtoMessage() {}
+
+func (x *Matchup) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_v1_archive_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Matchup.ProtoReflect.Descriptor instead.
+func (*Matchup) Descriptor() ([]byte, []int) {
+	return file_archive_v1_archive_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Matchup) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Matchup) GetWeek() int32 {
+	if x != nil {
+		return x.Week
+	}
+	return 0
+}
+
+func (x *Matchup) GetHomeTeamId() int32 {
+	if x != nil && x.HomeTeamId != nil {
+		return *x.HomeTeamId
+	}
+	return 0
+}
+
+func (x *Matchup) GetAwayTeamId() int32 {
+	if x != nil && x.AwayTeamId != nil {
+		return *x.AwayTeamId
+	}
+	return 0
+}
+
+func (x *Matchup) GetHomeScore() float64 {
+	if x != nil && x.HomeScore != nil {
+		return *x.HomeScore
+	}
+	return 0
+}
+
+func (x *Matchup) GetAwayScore() float64 {
+	if x != nil && x.AwayScore != nil {
+		return *x.AwayScore
+	}
+	return 0
+}
+
+func (x *Matchup) GetIsPlayoff() bool {
+	if x != nil && x.IsPlayoff != nil {
+		return *x.IsPlayoff
+	}
+	return false
+}
+
+func (x *Matchup) GetMatchupType() string {
+	if x != nil && x.MatchupType != nil {
+		return *x.MatchupType
+	}
+	return ""
+}
+
+type GetLeagueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLeagueRequest) Reset() {
+	*x = GetLeagueRequest{}
+	mi := &file_archive_v1_archive_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLeagueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLeagueRequest) ProtoMessage() {}
+
+func (x *GetLeagueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_v1_archive_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLeagueRequest.ProtoReflect.Descriptor instead.
+func (*GetLeagueRequest) Descriptor() ([]byte, []int) {
+	return file_archive_v1_archive_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetLeagueRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetLeagueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	League        *League                `protobuf:"bytes,1,opt,name=league,proto3" json:"league,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLeagueResponse) Reset() {
+	*x = GetLeagueResponse{}
+	mi := &file_archive_v1_archive_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLeagueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLeagueResponse) ProtoMessage() {}
+
+func (x *GetLeagueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_v1_archive_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLeagueResponse.ProtoReflect.Descriptor instead.
+func (*GetLeagueResponse) Descriptor() ([]byte, []int) {
+	return file_archive_v1_archive_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetLeagueResponse) GetLeague() *League {
+	if x != nil {
+		return x.League
+	}
+	return nil
+}
+
+// ListLeaguesRequest mirrors the query string accepted by GET /v1/leagues. Unset
+// filters match every league.
+type ListLeaguesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Sort          string                 `protobuf:"bytes,3,opt,name=sort,proto3" json:"sort,omitempty"`
+	Id            *int32                 `protobuf:"varint,4,opt,name=id,proto3,oneof" json:"id,omitempty"`
+	LeagueId      *int32                 `protobuf:"varint,5,opt,name=league_id,json=leagueId,proto3,oneof" json:"league_id,omitempty"`
+	Year          *int32                 `protobuf:"varint,6,opt,name=year,proto3,oneof" json:"year,omitempty"`
+	TeamCount     *int32                 `protobuf:"varint,7,opt,name=team_count,json=teamCount,proto3,oneof" json:"team_count,omitempty"`
+	CurrentWeek   *int32                 `protobuf:"varint,8,opt,name=current_week,json=currentWeek,proto3,oneof" json:"current_week,omitempty"`
+	NflWeek       *int32                 `protobuf:"varint,9,opt,name=nfl_week,json=nflWeek,proto3,oneof" json:"nfl_week,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLeaguesRequest) Reset() {
+	*x = ListLeaguesRequest{}
+	mi := &file_archive_v1_archive_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLeaguesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLeaguesRequest) ProtoMessage() {}
+
+func (x *ListLeaguesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_v1_archive_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLeaguesRequest.ProtoReflect.Descriptor instead.
+func (*ListLeaguesRequest) Descriptor() ([]byte, []int) {
+	return file_archive_v1_archive_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListLeaguesRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListLeaguesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListLeaguesRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListLeaguesRequest) GetId() int32 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *ListLeaguesRequest) GetLeagueId() int32 {
+	if x != nil && x.LeagueId != nil {
+		return *x.LeagueId
+	}
+	return 0
+}
+
+func (x *ListLeaguesRequest) GetYear() int32 {
+	if x != nil && x.Year != nil {
+		return *x.Year
+	}
+	return 0
+}
+
+func (x *ListLeaguesRequest) GetTeamCount() int32 {
+	if x != nil && x.TeamCount != nil {
+		return *x.TeamCount
+	}
+	return 0
+}
+
+func (x *ListLeaguesRequest) GetCurrentWeek() int32 {
+	if x != nil && x.CurrentWeek != nil {
+		return *x.CurrentWeek
+	}
+	return 0
+}
+
+func (x *ListLeaguesRequest) GetNflWeek() int32 {
+	if x != nil && x.NflWeek != nil {
+		return *x.NflWeek
+	}
+	return 0
+}
+
+type ListLeaguesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Leagues       []*League              `protobuf:"bytes,1,rep,name=leagues,proto3" json:"leagues,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLeaguesResponse) Reset() {
+	*x = ListLeaguesResponse{}
+	mi := &file_archive_v1_archive_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLeaguesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLeaguesResponse) ProtoMessage() {}
+
+func (x *ListLeaguesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_v1_archive_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLeaguesResponse.ProtoReflect.Descriptor instead.
+func (*ListLeaguesResponse) Descriptor() ([]byte, []int) {
+	return file_archive_v1_archive_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListLeaguesResponse) GetLeagues() []*League {
+	if x != nil {
+		return x.Leagues
+	}
+	return nil
+}
+
+type GetTeamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeamRequest) Reset() {
+	*x = GetTeamRequest{}
+	mi := &file_archive_v1_archive_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeamRequest) ProtoMessage() {}
+
+func (x *GetTeamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_v1_archive_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeamRequest.ProtoReflect.Descriptor instead.
+func (*GetTeamRequest) Descriptor() ([]byte, []int) {
+	return file_archive_v1_archive_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetTeamRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetTeamResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Team          *Team                  `protobuf:"bytes,1,opt,name=team,proto3" json:"team,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeamResponse) Reset() {
+	*x = GetTeamResponse{}
+	mi := &file_archive_v1_archive_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeamResponse) ProtoMessage() {}
+
+func (x *GetTeamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_v1_archive_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeamResponse.ProtoReflect.Descriptor instead.
+func (*GetTeamResponse) Descriptor() ([]byte, []int) {
+	return file_archive_v1_archive_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetTeamResponse) GetTeam() *Team {
+	if x != nil {
+		return x.Team
+	}
+	return nil
+}
+
+type ListTeamsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LeagueId      int32                  `protobuf:"varint,1,opt,name=league_id,json=leagueId,proto3" json:"league_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTeamsRequest) Reset() {
+	*x = ListTeamsRequest{}
+	mi := &file_archive_v1_archive_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTeamsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTeamsRequest) ProtoMessage() {}
+
+func (x *ListTeamsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_v1_archive_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTeamsRequest.ProtoReflect.Descriptor instead.
+func (*ListTeamsRequest) Descriptor() ([]byte, []int) {
+	return file_archive_v1_archive_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListTeamsRequest) GetLeagueId() int32 {
+	if x != nil {
+		return x.LeagueId
+	}
+	return 0
+}
+
+type ListTeamsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Teams         []*Team                `protobuf:"bytes,1,rep,name=teams,proto3" json:"teams,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTeamsResponse) Reset() {
+	*x = ListTeamsResponse{}
+	mi := &file_archive_v1_archive_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTeamsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTeamsResponse) ProtoMessage() {}
+
+func (x *ListTeamsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_v1_archive_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTeamsResponse.ProtoReflect.Descriptor instead.
+func (*ListTeamsResponse) Descriptor() ([]byte, []int) {
+	return file_archive_v1_archive_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListTeamsResponse) GetTeams() []*Team {
+	if x != nil {
+		return x.Teams
+	}
+	return nil
+}
+
+type ListMatchupsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeamId        int32                  `protobuf:"varint,1,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMatchupsRequest) Reset() {
+	*x = ListMatchupsRequest{}
+	mi := &file_archive_v1_archive_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMatchupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMatchupsRequest) ProtoMessage() {}
+
+func (x *ListMatchupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_v1_archive_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMatchupsRequest.ProtoReflect.Descriptor instead.
+func (*ListMatchupsRequest) Descriptor() ([]byte, []int) {
+	return file_archive_v1_archive_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListMatchupsRequest) GetTeamId() int32 {
+	if x != nil {
+		return x.TeamId
+	}
+	return 0
+}
+
+type ListMatchupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matchups      []*Matchup             `protobuf:"bytes,1,rep,name=matchups,proto3" json:"matchups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMatchupsResponse) Reset() {
+	*x = ListMatchupsResponse{}
+	mi := &file_archive_v1_archive_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMatchupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMatchupsResponse) ProtoMessage() {}
+
+func (x *ListMatchupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_v1_archive_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMatchupsResponse.ProtoReflect.Descriptor instead.
+func (*ListMatchupsResponse) Descriptor() ([]byte, []int) {
+	return file_archive_v1_archive_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListMatchupsResponse) GetMatchups() []*Matchup {
+	if x != nil {
+		return x.Matchups
+	}
+	return nil
+}
+
+var File_archive_v1_archive_proto protoreflect.FileDescriptor
+
+var file_archive_v1_archive_proto_rawDesc = string([]byte{
+	0x0a, 0x18, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x72, 0x63,
+	0x68, 0x69, 0x76, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x61, 0x72, 0x63, 0x68,
+	0x69, 0x76, 0x65, 0x2e, 0x76, 0x31, 0x22, 0xa6, 0x01, 0x0a, 0x06, 0x4c, 0x65, 0x61, 0x67, 0x75,
+	0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x49, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x79, 0x65,
+	0x61, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x74, 0x65, 0x61, 0x6d, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x77, 0x65, 0x65,
+	0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74,
+	0x57, 0x65, 0x65, 0x6b, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x66, 0x6c, 0x5f, 0x77, 0x65, 0x65, 0x6b,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x6e, 0x66, 0x6c, 0x57, 0x65, 0x65, 0x6b, 0x22,
+	0xb0, 0x09, 0x0a, 0x04, 0x54, 0x65, 0x61, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x65, 0x61, 0x67,
+	0x75, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6c, 0x65, 0x61,
+	0x67, 0x75, 0x65, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x69, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x74, 0x65, 0x61, 0x6d, 0x49, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x79, 0x65,
+	0x61, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x61, 0x62, 0x62, 0x72, 0x76,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x65, 0x61, 0x6d, 0x41, 0x62, 0x62, 0x72,
+	0x76, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x65, 0x61, 0x6d, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b,
+	0x0a, 0x06, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00,
+	0x52, 0x06, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x64,
+	0x69, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x01, 0x52, 0x0a, 0x64, 0x69, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88, 0x01,
+	0x01, 0x12, 0x28, 0x0a, 0x0d, 0x64, 0x69, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0c, 0x64, 0x69, 0x76, 0x69,
+	0x73, 0x69, 0x6f, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x77,
+	0x69, 0x6e, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x48, 0x03, 0x52, 0x04, 0x77, 0x69, 0x6e,
+	0x73, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x6c, 0x6f, 0x73, 0x73, 0x65, 0x73, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x05, 0x48, 0x04, 0x52, 0x06, 0x6c, 0x6f, 0x73, 0x73, 0x65, 0x73, 0x88, 0x01,
+	0x01, 0x12, 0x17, 0x0a, 0x04, 0x74, 0x69, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x05, 0x52, 0x04, 0x74, 0x69, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x73, 0x5f, 0x66, 0x6f, 0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x48, 0x06,
+	0x52, 0x09, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x46, 0x6f, 0x72, 0x88, 0x01, 0x01, 0x12, 0x2a,
+	0x0a, 0x0e, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x5f, 0x61, 0x67, 0x61, 0x69, 0x6e, 0x73, 0x74,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x05, 0x48, 0x07, 0x52, 0x0d, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73,
+	0x41, 0x67, 0x61, 0x69, 0x6e, 0x73, 0x74, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x77, 0x61,
+	0x69, 0x76, 0x65, 0x72, 0x5f, 0x72, 0x61, 0x6e, 0x6b, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x08, 0x52, 0x0a, 0x77, 0x61, 0x69, 0x76, 0x65, 0x72, 0x52, 0x61, 0x6e, 0x6b, 0x88, 0x01, 0x01,
+	0x12, 0x27, 0x0a, 0x0c, 0x61, 0x63, 0x71, 0x75, 0x69, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x10, 0x20, 0x01, 0x28, 0x05, 0x48, 0x09, 0x52, 0x0c, 0x61, 0x63, 0x71, 0x75, 0x69, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x88, 0x01, 0x01, 0x12, 0x3d, 0x0a, 0x18, 0x61, 0x63, 0x71,
+	0x75, 0x69, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x62, 0x75, 0x64, 0x67, 0x65, 0x74, 0x5f,
+	0x73, 0x70, 0x65, 0x6e, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x05, 0x48, 0x0a, 0x52, 0x16, 0x61,
+	0x63, 0x71, 0x75, 0x69, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74,
+	0x53, 0x70, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x64, 0x72, 0x6f, 0x70,
+	0x73, 0x18, 0x12, 0x20, 0x01, 0x28, 0x05, 0x48, 0x0b, 0x52, 0x05, 0x64, 0x72, 0x6f, 0x70, 0x73,
+	0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x18, 0x13, 0x20,
+	0x01, 0x28, 0x05, 0x48, 0x0c, 0x52, 0x06, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x88, 0x01, 0x01,
+	0x12, 0x24, 0x0a, 0x0b, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6b, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x14, 0x20, 0x01, 0x28, 0x09, 0x48, 0x0d, 0x52, 0x0a, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6b, 0x54,
+	0x79, 0x70, 0x65, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6b,
+	0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x15, 0x20, 0x01, 0x28, 0x05, 0x48, 0x0e, 0x52,
+	0x0c, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6b, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x88, 0x01, 0x01,
+	0x12, 0x1f, 0x0a, 0x08, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x16, 0x20, 0x01,
+	0x28, 0x05, 0x48, 0x0f, 0x52, 0x08, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x88, 0x01,
+	0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x73, 0x74, 0x61, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x18, 0x17, 0x20, 0x01, 0x28, 0x05, 0x48, 0x10, 0x52, 0x0d, 0x66, 0x69, 0x6e,
+	0x61, 0x6c, 0x53, 0x74, 0x61, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a,
+	0x0f, 0x64, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6a, 0x5f, 0x72, 0x61, 0x6e, 0x6b,
+	0x18, 0x18, 0x20, 0x01, 0x28, 0x05, 0x48, 0x11, 0x52, 0x0d, 0x64, 0x72, 0x61, 0x66, 0x74, 0x50,
+	0x72, 0x6f, 0x6a, 0x52, 0x61, 0x6e, 0x6b, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x70, 0x6c,
+	0x61, 0x79, 0x6f, 0x66, 0x66, 0x5f, 0x70, 0x63, 0x74, 0x18, 0x19, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x12, 0x52, 0x0a, 0x70, 0x6c, 0x61, 0x79, 0x6f, 0x66, 0x66, 0x50, 0x63, 0x74, 0x88, 0x01, 0x01,
+	0x12, 0x1e, 0x0a, 0x08, 0x6c, 0x6f, 0x67, 0x6f, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x1a, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x13, 0x52, 0x07, 0x6c, 0x6f, 0x67, 0x6f, 0x55, 0x72, 0x6c, 0x88, 0x01, 0x01,
+	0x42, 0x09, 0x0a, 0x07, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x5f,
+	0x64, 0x69, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x42, 0x10, 0x0a, 0x0e, 0x5f,
+	0x64, 0x69, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x07, 0x0a,
+	0x05, 0x5f, 0x77, 0x69, 0x6e, 0x73, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x6c, 0x6f, 0x73, 0x73, 0x65,
+	0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x74, 0x69, 0x65, 0x73, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x73, 0x5f, 0x66, 0x6f, 0x72, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x73, 0x5f, 0x61, 0x67, 0x61, 0x69, 0x6e, 0x73, 0x74, 0x42, 0x0e, 0x0a, 0x0c,
+	0x5f, 0x77, 0x61, 0x69, 0x76, 0x65, 0x72, 0x5f, 0x72, 0x61, 0x6e, 0x6b, 0x42, 0x0f, 0x0a, 0x0d,
+	0x5f, 0x61, 0x63, 0x71, 0x75, 0x69, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x42, 0x1b, 0x0a,
+	0x19, 0x5f, 0x61, 0x63, 0x71, 0x75, 0x69, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x62, 0x75,
+	0x64, 0x67, 0x65, 0x74, 0x5f, 0x73, 0x70, 0x65, 0x6e, 0x74, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x64,
+	0x72, 0x6f, 0x70, 0x73, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x42,
+	0x0e, 0x0a, 0x0c, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6b, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x42,
+	0x10, 0x0a, 0x0e, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6b, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74,
+	0x68, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x42, 0x11,
+	0x0a, 0x0f, 0x5f, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x64, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6a,
+	0x5f, 0x72, 0x61, 0x6e, 0x6b, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x70, 0x6c, 0x61, 0x79, 0x6f, 0x66,
+	0x66, 0x5f, 0x70, 0x63, 0x74, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6c, 0x6f, 0x67, 0x6f, 0x5f, 0x75,
+	0x72, 0x6c, 0x22, 0xef, 0x02, 0x0a, 0x07, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x75, 0x70, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x77, 0x65, 0x65, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x77, 0x65,
+	0x65, 0x6b, 0x12, 0x25, 0x0a, 0x0c, 0x68, 0x6f, 0x6d, 0x65, 0x5f, 0x74, 0x65, 0x61, 0x6d, 0x5f,
+	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x0a, 0x68, 0x6f, 0x6d, 0x65,
+	0x54, 0x65, 0x61, 0x6d, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0c, 0x61, 0x77, 0x61,
+	0x79, 0x5f, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x01, 0x52, 0x0a, 0x61, 0x77, 0x61, 0x79, 0x54, 0x65, 0x61, 0x6d, 0x49, 0x64, 0x88, 0x01, 0x01,
+	0x12, 0x22, 0x0a, 0x0a, 0x68, 0x6f, 0x6d, 0x65, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x01, 0x48, 0x02, 0x52, 0x09, 0x68, 0x6f, 0x6d, 0x65, 0x53, 0x63, 0x6f, 0x72,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x61, 0x77, 0x61, 0x79, 0x5f, 0x73, 0x63, 0x6f,
+	0x72, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x48, 0x03, 0x52, 0x09, 0x61, 0x77, 0x61, 0x79,
+	0x53, 0x63, 0x6f, 0x72, 0x65, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x69, 0x73, 0x5f, 0x70,
+	0x6c, 0x61, 0x79, 0x6f, 0x66, 0x66, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x48, 0x04, 0x52, 0x09,
+	0x69, 0x73, 0x50, 0x6c, 0x61, 0x79, 0x6f, 0x66, 0x66, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x75, 0x70, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x05, 0x52, 0x0b, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x75, 0x70, 0x54, 0x79, 0x70,
+	0x65, 0x88, 0x01, 0x01, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x68, 0x6f, 0x6d, 0x65, 0x5f, 0x74, 0x65,
+	0x61, 0x6d, 0x5f, 0x69, 0x64, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x61, 0x77, 0x61, 0x79, 0x5f, 0x74,
+	0x65, 0x61, 0x6d, 0x5f, 0x69, 0x64, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x68, 0x6f, 0x6d, 0x65, 0x5f,
+	0x73, 0x63, 0x6f, 0x72, 0x65, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x61, 0x77, 0x61, 0x79, 0x5f, 0x73,
+	0x63, 0x6f, 0x72, 0x65, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x69, 0x73, 0x5f, 0x70, 0x6c, 0x61, 0x79,
+	0x6f, 0x66, 0x66, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x75, 0x70, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x22, 0x22, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x67, 0x75,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x3f, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x4c,
+	0x65, 0x61, 0x67, 0x75, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a,
+	0x06, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x65, 0x61, 0x67, 0x75,
+	0x65, 0x52, 0x06, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x22, 0xe0, 0x02, 0x0a, 0x12, 0x4c, 0x69,
+	0x73, 0x74, 0x4c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x70, 0x61, 0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x73, 0x6f, 0x72, 0x74, 0x12, 0x13, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x05, 0x48, 0x00, 0x52, 0x02, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x09, 0x6c, 0x65,
+	0x61, 0x67, 0x75, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52,
+	0x08, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04,
+	0x79, 0x65, 0x61, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x48, 0x02, 0x52, 0x04, 0x79, 0x65,
+	0x61, 0x72, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x48, 0x03, 0x52, 0x09, 0x74, 0x65, 0x61,
+	0x6d, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x74, 0x5f, 0x77, 0x65, 0x65, 0x6b, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x04, 0x52, 0x0b, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x57, 0x65, 0x65, 0x6b, 0x88, 0x01,
+	0x01, 0x12, 0x1e, 0x0a, 0x08, 0x6e, 0x66, 0x6c, 0x5f, 0x77, 0x65, 0x65, 0x6b, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x05, 0x48, 0x05, 0x52, 0x07, 0x6e, 0x66, 0x6c, 0x57, 0x65, 0x65, 0x6b, 0x88, 0x01,
+	0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69, 0x64, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6c, 0x65, 0x61,
+	0x67, 0x75, 0x65, 0x5f, 0x69, 0x64, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x79, 0x65, 0x61, 0x72, 0x42,
+	0x0d, 0x0a, 0x0b, 0x5f, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x0f,
+	0x0a, 0x0d, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x77, 0x65, 0x65, 0x6b, 0x42,
+	0x0b, 0x0a, 0x09, 0x5f, 0x6e, 0x66, 0x6c, 0x5f, 0x77, 0x65, 0x65, 0x6b, 0x22, 0x43, 0x0a, 0x13,
+	0x4c, 0x69, 0x73, 0x74, 0x4c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x52, 0x07, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65,
+	0x73, 0x22, 0x20, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x54, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x02, 0x69, 0x64, 0x22, 0x37, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x54, 0x65, 0x61, 0x6d, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x54, 0x65, 0x61, 0x6d, 0x52, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x22, 0x2f, 0x0a, 0x10,
+	0x4c, 0x69, 0x73, 0x74, 0x54, 0x65, 0x61, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x49, 0x64, 0x22, 0x3b, 0x0a,
+	0x11, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x65, 0x61, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x26, 0x0a, 0x05, 0x74, 0x65, 0x61, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54,
+	0x65, 0x61, 0x6d, 0x52, 0x05, 0x74, 0x65, 0x61, 0x6d, 0x73, 0x22, 0x2e, 0x0a, 0x13, 0x4c, 0x69,
+	0x73, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x06, 0x74, 0x65, 0x61, 0x6d, 0x49, 0x64, 0x22, 0x47, 0x0a, 0x14, 0x4c, 0x69,
+	0x73, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x75, 0x70, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x2f, 0x0a, 0x08, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x75, 0x70, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x75, 0x70, 0x52, 0x08, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x75, 0x70, 0x73, 0x32, 0xa4, 0x03, 0x0a, 0x0e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4d, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x4c, 0x65, 0x61,
+	0x67, 0x75, 0x65, 0x12, 0x1c, 0x2e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1d, 0x2e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x65, 0x74, 0x4c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x03, 0x90, 0x02, 0x01, 0x12, 0x53, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x65, 0x61,
+	0x67, 0x75, 0x65, 0x73, 0x12, 0x1e, 0x2e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x03, 0x90, 0x02, 0x01, 0x12, 0x47, 0x0a, 0x07, 0x47, 0x65,
+	0x74, 0x54, 0x65, 0x61, 0x6d, 0x12, 0x1a, 0x2e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1b, 0x2e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x65, 0x74, 0x54, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x03,
+	0x90, 0x02, 0x01, 0x12, 0x4d, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x65, 0x61, 0x6d, 0x73,
+	0x12, 0x1c, 0x2e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x54, 0x65, 0x61, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d,
+	0x2e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x54, 0x65, 0x61, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x03, 0x90,
+	0x02, 0x01, 0x12, 0x56, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x75,
+	0x70, 0x73, 0x12, 0x1f, 0x2e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x75, 0x70, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x03, 0x90, 0x02, 0x01, 0x42, 0x49, 0x5a, 0x47, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x38, 0x73,
+	0x2f, 0x68, 0x6f, 0x6d, 0x65, 0x2d, 0x64, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x2d,
+	0x61, 0x70, 0x70, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x67, 0x65, 0x6e,
+	0x2f, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2f, 0x76, 0x31, 0x3b, 0x61, 0x72, 0x63, 0x68,
+	0x69, 0x76, 0x65, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_archive_v1_archive_proto_rawDescOnce sync.Once
+	file_archive_v1_archive_proto_rawDescData []byte
+)
+
+func file_archive_v1_archive_proto_rawDescGZIP() []byte {
+	file_archive_v1_archive_proto_rawDescOnce.Do(func() {
+		file_archive_v1_archive_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_archive_v1_archive_proto_rawDesc), len(file_archive_v1_archive_proto_rawDesc)))
+	})
+	return file_archive_v1_archive_proto_rawDescData
+}
+
+var file_archive_v1_archive_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_archive_v1_archive_proto_goTypes = []any{
+	(*League)(nil),               // 0: archive.v1.League
+	(*Team)(nil),                 // 1: archive.v1.Team
+	(*Matchup)(nil),              // 2: archive.v1.Matchup
+	(*GetLeagueRequest)(nil),     // 3: archive.v1.GetLeagueRequest
+	(*GetLeagueResponse)(nil),    // 4: archive.v1.GetLeagueResponse
+	(*ListLeaguesRequest)(nil),   // 5: archive.v1.ListLeaguesRequest
+	(*ListLeaguesResponse)(nil),  // 6: archive.v1.ListLeaguesResponse
+	(*GetTeamRequest)(nil),       // 7: archive.v1.GetTeamRequest
+	(*GetTeamResponse)(nil),      // 8: archive.v1.GetTeamResponse
+	(*ListTeamsRequest)(nil),     // 9: archive.v1.ListTeamsRequest
+	(*ListTeamsResponse)(nil),    // 10: archive.v1.ListTeamsResponse
+	(*ListMatchupsRequest)(nil),  // 11: archive.v1.ListMatchupsRequest
+	(*ListMatchupsResponse)(nil), // 12: archive.v1.ListMatchupsResponse
+}
+var file_archive_v1_archive_proto_depIdxs = []int32{
+	0,  // 0: archive.v1.GetLeagueResponse.league:type_name -> archive.v1.League
+	0,  // 1: archive.v1.ListLeaguesResponse.leagues:type_name -> archive.v1.League
+	1,  // 2: archive.v1.GetTeamResponse.team:type_name -> archive.v1.Team
+	1,  // 3: archive.v1.ListTeamsResponse.teams:type_name -> archive.v1.Team
+	2,  // 4: archive.v1.ListMatchupsResponse.matchups:type_name -> archive.v1.Matchup
+	3,  // 5: archive.v1.ArchiveService.GetLeague:input_type -> archive.v1.GetLeagueRequest
+	5,  // 6: archive.v1.ArchiveService.ListLeagues:input_type -> archive.v1.ListLeaguesRequest
+	7,  // 7: archive.v1.ArchiveService.GetTeam:input_type -> archive.v1.GetTeamRequest
+	9,  // 8: archive.v1.ArchiveService.ListTeams:input_type -> archive.v1.ListTeamsRequest
+	11, // 9: archive.v1.ArchiveService.ListMatchups:input_type -> archive.v1.ListMatchupsRequest
+	4,  // 10: archive.v1.ArchiveService.GetLeague:output_type -> archive.v1.GetLeagueResponse
+	6,  // 11: archive.v1.ArchiveService.ListLeagues:output_type -> archive.v1.ListLeaguesResponse
+	8,  // 12: archive.v1.ArchiveService.GetTeam:output_type -> archive.v1.GetTeamResponse
+	10, // 13: archive.v1.ArchiveService.ListTeams:output_type -> archive.v1.ListTeamsResponse
+	12, // 14: archive.v1.ArchiveService.ListMatchups:output_type -> archive.v1.ListMatchupsResponse
+	10, // [10:15] is the sub-list for method output_type
+	5,  // [5:10] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_archive_v1_archive_proto_init() }
+func file_archive_v1_archive_proto_init() {
+	if File_archive_v1_archive_proto != nil {
+		return
+	}
+	file_archive_v1_archive_proto_msgTypes[1].OneofWrappers = []any{}
+	file_archive_v1_archive_proto_msgTypes[2].OneofWrappers = []any{}
+	file_archive_v1_archive_proto_msgTypes[5].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_archive_v1_archive_proto_rawDesc), len(file_archive_v1_archive_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_archive_v1_archive_proto_goTypes,
+		DependencyIndexes: file_archive_v1_archive_proto_depIdxs,
+		MessageInfos:      file_archive_v1_archive_proto_msgTypes,
+	}.Build()
+	File_archive_v1_archive_proto = out.File
+	file_archive_v1_archive_proto_goTypes = nil
+	file_archive_v1_archive_proto_depIdxs = nil
+}
@@ -0,0 +1,234 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: archive/v1/archive.proto
+
+package archivev1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/layer8s/home-dashboard-app/internal/gen/archive/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// ArchiveServiceName is the fully-qualified name of the ArchiveService service.
+	ArchiveServiceName = "archive.v1.ArchiveService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// ArchiveServiceGetLeagueProcedure is the fully-qualified name of the ArchiveService's GetLeague
+	// RPC.
+	ArchiveServiceGetLeagueProcedure = "/archive.v1.ArchiveService/GetLeague"
+	// ArchiveServiceListLeaguesProcedure is the fully-qualified name of the ArchiveService's
+	// ListLeagues RPC.
+	ArchiveServiceListLeaguesProcedure = "/archive.v1.ArchiveService/ListLeagues"
+	// ArchiveServiceGetTeamProcedure is the fully-qualified name of the ArchiveService's GetTeam RPC.
+	ArchiveServiceGetTeamProcedure = "/archive.v1.ArchiveService/GetTeam"
+	// ArchiveServiceListTeamsProcedure is the fully-qualified name of the ArchiveService's ListTeams
+	// RPC.
+	ArchiveServiceListTeamsProcedure = "/archive.v1.ArchiveService/ListTeams"
+	// ArchiveServiceListMatchupsProcedure is the fully-qualified name of the ArchiveService's
+	// ListMatchups RPC.
+	ArchiveServiceListMatchupsProcedure = "/archive.v1.ArchiveService/ListMatchups"
+)
+
+// ArchiveServiceClient is a client for the archive.v1.ArchiveService service.
+type ArchiveServiceClient interface {
+	GetLeague(context.Context, *connect.Request[v1.GetLeagueRequest]) (*connect.Response[v1.GetLeagueResponse], error)
+	ListLeagues(context.Context, *connect.Request[v1.ListLeaguesRequest]) (*connect.Response[v1.ListLeaguesResponse], error)
+	GetTeam(context.Context, *connect.Request[v1.GetTeamRequest]) (*connect.Response[v1.GetTeamResponse], error)
+	ListTeams(context.Context, *connect.Request[v1.ListTeamsRequest]) (*connect.Response[v1.ListTeamsResponse], error)
+	ListMatchups(context.Context, *connect.Request[v1.ListMatchupsRequest]) (*connect.Response[v1.ListMatchupsResponse], error)
+}
+
+// NewArchiveServiceClient constructs a client for the archive.v1.ArchiveService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewArchiveServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) ArchiveServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	archiveServiceMethods := v1.File_archive_v1_archive_proto.Services().ByName("ArchiveService").Methods()
+	return &archiveServiceClient{
+		getLeague: connect.NewClient[v1.GetLeagueRequest, v1.GetLeagueResponse](
+			httpClient,
+			baseURL+ArchiveServiceGetLeagueProcedure,
+			connect.WithSchema(archiveServiceMethods.ByName("GetLeague")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		listLeagues: connect.NewClient[v1.ListLeaguesRequest, v1.ListLeaguesResponse](
+			httpClient,
+			baseURL+ArchiveServiceListLeaguesProcedure,
+			connect.WithSchema(archiveServiceMethods.ByName("ListLeagues")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getTeam: connect.NewClient[v1.GetTeamRequest, v1.GetTeamResponse](
+			httpClient,
+			baseURL+ArchiveServiceGetTeamProcedure,
+			connect.WithSchema(archiveServiceMethods.ByName("GetTeam")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		listTeams: connect.NewClient[v1.ListTeamsRequest, v1.ListTeamsResponse](
+			httpClient,
+			baseURL+ArchiveServiceListTeamsProcedure,
+			connect.WithSchema(archiveServiceMethods.ByName("ListTeams")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		listMatchups: connect.NewClient[v1.ListMatchupsRequest, v1.ListMatchupsResponse](
+			httpClient,
+			baseURL+ArchiveServiceListMatchupsProcedure,
+			connect.WithSchema(archiveServiceMethods.ByName("ListMatchups")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// archiveServiceClient implements ArchiveServiceClient.
+type archiveServiceClient struct {
+	getLeague    *connect.Client[v1.GetLeagueRequest, v1.GetLeagueResponse]
+	listLeagues  *connect.Client[v1.ListLeaguesRequest, v1.ListLeaguesResponse]
+	getTeam      *connect.Client[v1.GetTeamRequest, v1.GetTeamResponse]
+	listTeams    *connect.Client[v1.ListTeamsRequest, v1.ListTeamsResponse]
+	listMatchups *connect.Client[v1.ListMatchupsRequest, v1.ListMatchupsResponse]
+}
+
+// GetLeague calls archive.v1.ArchiveService.GetLeague.
+func (c *archiveServiceClient) GetLeague(ctx context.Context, req *connect.Request[v1.GetLeagueRequest]) (*connect.Response[v1.GetLeagueResponse], error) {
+	return c.getLeague.CallUnary(ctx, req)
+}
+
+// ListLeagues calls archive.v1.ArchiveService.ListLeagues.
+func (c *archiveServiceClient) ListLeagues(ctx context.Context, req *connect.Request[v1.ListLeaguesRequest]) (*connect.Response[v1.ListLeaguesResponse], error) {
+	return c.listLeagues.CallUnary(ctx, req)
+}
+
+// GetTeam calls archive.v1.ArchiveService.GetTeam.
+func (c *archiveServiceClient) GetTeam(ctx context.Context, req *connect.Request[v1.GetTeamRequest]) (*connect.Response[v1.GetTeamResponse], error) {
+	return c.getTeam.CallUnary(ctx, req)
+}
+
+// ListTeams calls archive.v1.ArchiveService.ListTeams.
+func (c *archiveServiceClient) ListTeams(ctx context.Context, req *connect.Request[v1.ListTeamsRequest]) (*connect.Response[v1.ListTeamsResponse], error) {
+	return c.listTeams.CallUnary(ctx, req)
+}
+
+// ListMatchups calls archive.v1.ArchiveService.ListMatchups.
+func (c *archiveServiceClient) ListMatchups(ctx context.Context, req *connect.Request[v1.ListMatchupsRequest]) (*connect.Response[v1.ListMatchupsResponse], error) {
+	return c.listMatchups.CallUnary(ctx, req)
+}
+
+// ArchiveServiceHandler is an implementation of the archive.v1.ArchiveService service.
+type ArchiveServiceHandler interface {
+	GetLeague(context.Context, *connect.Request[v1.GetLeagueRequest]) (*connect.Response[v1.GetLeagueResponse], error)
+	ListLeagues(context.Context, *connect.Request[v1.ListLeaguesRequest]) (*connect.Response[v1.ListLeaguesResponse], error)
+	GetTeam(context.Context, *connect.Request[v1.GetTeamRequest]) (*connect.Response[v1.GetTeamResponse], error)
+	ListTeams(context.Context, *connect.Request[v1.ListTeamsRequest]) (*connect.Response[v1.ListTeamsResponse], error)
+	ListMatchups(context.Context, *connect.Request[v1.ListMatchupsRequest]) (*connect.Response[v1.ListMatchupsResponse], error)
+}
+
+// NewArchiveServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewArchiveServiceHandler(svc ArchiveServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	archiveServiceMethods := v1.File_archive_v1_archive_proto.Services().ByName("ArchiveService").Methods()
+	archiveServiceGetLeagueHandler := connect.NewUnaryHandler(
+		ArchiveServiceGetLeagueProcedure,
+		svc.GetLeague,
+		connect.WithSchema(archiveServiceMethods.ByName("GetLeague")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	archiveServiceListLeaguesHandler := connect.NewUnaryHandler(
+		ArchiveServiceListLeaguesProcedure,
+		svc.ListLeagues,
+		connect.WithSchema(archiveServiceMethods.ByName("ListLeagues")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	archiveServiceGetTeamHandler := connect.NewUnaryHandler(
+		ArchiveServiceGetTeamProcedure,
+		svc.GetTeam,
+		connect.WithSchema(archiveServiceMethods.ByName("GetTeam")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	archiveServiceListTeamsHandler := connect.NewUnaryHandler(
+		ArchiveServiceListTeamsProcedure,
+		svc.ListTeams,
+		connect.WithSchema(archiveServiceMethods.ByName("ListTeams")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	archiveServiceListMatchupsHandler := connect.NewUnaryHandler(
+		ArchiveServiceListMatchupsProcedure,
+		svc.ListMatchups,
+		connect.WithSchema(archiveServiceMethods.ByName("ListMatchups")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/archive.v1.ArchiveService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case ArchiveServiceGetLeagueProcedure:
+			archiveServiceGetLeagueHandler.ServeHTTP(w, r)
+		case ArchiveServiceListLeaguesProcedure:
+			archiveServiceListLeaguesHandler.ServeHTTP(w, r)
+		case ArchiveServiceGetTeamProcedure:
+			archiveServiceGetTeamHandler.ServeHTTP(w, r)
+		case ArchiveServiceListTeamsProcedure:
+			archiveServiceListTeamsHandler.ServeHTTP(w, r)
+		case ArchiveServiceListMatchupsProcedure:
+			archiveServiceListMatchupsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedArchiveServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedArchiveServiceHandler struct{}
+
+func (UnimplementedArchiveServiceHandler) GetLeague(context.Context, *connect.Request[v1.GetLeagueRequest]) (*connect.Response[v1.GetLeagueResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("archive.v1.ArchiveService.GetLeague is not implemented"))
+}
+
+func (UnimplementedArchiveServiceHandler) ListLeagues(context.Context, *connect.Request[v1.ListLeaguesRequest]) (*connect.Response[v1.ListLeaguesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("archive.v1.ArchiveService.ListLeagues is not implemented"))
+}
+
+func (UnimplementedArchiveServiceHandler) GetTeam(context.Context, *connect.Request[v1.GetTeamRequest]) (*connect.Response[v1.GetTeamResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("archive.v1.ArchiveService.GetTeam is not implemented"))
+}
+
+func (UnimplementedArchiveServiceHandler) ListTeams(context.Context, *connect.Request[v1.ListTeamsRequest]) (*connect.Response[v1.ListTeamsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("archive.v1.ArchiveService.ListTeams is not implemented"))
+}
+
+func (UnimplementedArchiveServiceHandler) ListMatchups(context.Context, *connect.Request[v1.ListMatchupsRequest]) (*connect.Response[v1.ListMatchupsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("archive.v1.ArchiveService.ListMatchups is not implemented"))
+}
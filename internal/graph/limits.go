@@ -0,0 +1,150 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// MaxListLimit is the largest page size accepted by paginated root fields.
+const MaxListLimit = 100
+
+// defaultListCost is the assumed number of items returned by a list field that has
+// no limit argument, used when estimating the complexity of a query.
+const defaultListCost = 10
+
+// Limits caps the shape of the queries the server is willing to execute.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// CheckLimits walks every operation in doc and rejects it if its selection depth or
+// estimated complexity exceeds the limits. Complexity counts one per selected field;
+// fields returning a list multiply the cost of their sub-selection by the value of
+// their limit argument, or by defaultListCost when they have none.
+func CheckLimits(schema graphql.Schema, doc *ast.Document, limits Limits) error {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		w := &limitWalker{schema: schema, fragments: fragments, visiting: make(map[string]bool)}
+		var root graphql.Type
+		if op.Operation == ast.OperationTypeQuery {
+			root = schema.QueryType()
+		}
+		depth, cost := w.walk(op.SelectionSet, root, 0)
+
+		if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+			return fmt.Errorf("query depth %d exceeds the maximum of %d", depth, limits.MaxDepth)
+		}
+		if limits.MaxComplexity > 0 && cost > limits.MaxComplexity {
+			return fmt.Errorf("query complexity %d exceeds the maximum of %d", cost, limits.MaxComplexity)
+		}
+	}
+	return nil
+}
+
+type limitWalker struct {
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	visiting  map[string]bool
+}
+
+// walk returns the depth and cost of a selection set whose parent has type parent.
+// parent may be nil (for instance inside introspection fields), in which case list
+// multipliers can't be applied but depth and field counts still are.
+func (w *limitWalker) walk(set *ast.SelectionSet, parent graphql.Type, depth int) (int, int) {
+	if set == nil {
+		return depth, 0
+	}
+
+	maxDepth, cost := depth, 0
+	for _, selection := range set.Selections {
+		var d, c int
+		switch sel := selection.(type) {
+		case *ast.Field:
+			d, c = w.walkField(sel, parent, depth)
+		case *ast.InlineFragment:
+			fragmentType := parent
+			if sel.TypeCondition != nil {
+				fragmentType = w.schema.Type(sel.TypeCondition.Name.Value)
+			}
+			d, c = w.walk(sel.SelectionSet, fragmentType, depth)
+		case *ast.FragmentSpread:
+			name := sel.Name.Value
+			fragment, ok := w.fragments[name]
+			if !ok || w.visiting[name] {
+				// Unknown or cyclic fragments are reported by query validation.
+				continue
+			}
+			w.visiting[name] = true
+			d, c = w.walk(fragment.SelectionSet, w.schema.Type(fragment.TypeCondition.Name.Value), depth)
+			w.visiting[name] = false
+		}
+		maxDepth = max(maxDepth, d)
+		cost += c
+	}
+	return maxDepth, cost
+}
+
+func (w *limitWalker) walkField(field *ast.Field, parent graphql.Type, depth int) (int, int) {
+	if field.Name.Value == "__typename" {
+		return depth, 0
+	}
+
+	var fieldType graphql.Type
+	if object, ok := parent.(*graphql.Object); ok {
+		if def, ok := object.Fields()[field.Name.Value]; ok {
+			fieldType = def.Type
+		}
+	}
+
+	isList := false
+	for {
+		if nonNull, ok := fieldType.(*graphql.NonNull); ok {
+			fieldType = nonNull.OfType
+			continue
+		}
+		if list, ok := fieldType.(*graphql.List); ok {
+			isList = true
+			fieldType = list.OfType
+			continue
+		}
+		break
+	}
+
+	childDepth, childCost := w.walk(field.SelectionSet, fieldType, depth+1)
+	if isList {
+		childCost *= listCost(field)
+	}
+	return childDepth, 1 + childCost
+}
+
+// listCost returns the expected length of a list field, taken from a literal limit
+// argument when one is given.
+func listCost(field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "limit" {
+			continue
+		}
+		if value, ok := arg.Value.(*ast.IntValue); ok {
+			if n, err := strconv.Atoi(value.Value); err == nil && n > 0 {
+				return min(n, MaxListLimit)
+			}
+		}
+		return MaxListLimit
+	}
+	return defaultListCost
+}
@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+func TestCheckLimits(t *testing.T) {
+	schema, _, _ := newTestSchema(t)
+	limits := Limits{MaxDepth: 4, MaxComplexity: 2000}
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"shallow", `{ leagues { id year } }`, ""},
+		{"at the depth limit", `{ leagues { teams { matchups { id } } } }`, ""},
+		{"too deep", `{ leagues { teams { matchups { homeTeam { id } } } } }`, "query depth 5 exceeds the maximum of 4"},
+		{"too deep through a fragment", `{ leagues { ...L } } fragment L on League { teams { matchups { homeTeam { id } } } }`, "query depth 5"},
+		{"too deep through an inline fragment", `{ leagues { ... on League { teams { matchups { homeTeam { id } } } } } }`, "query depth 5"},
+		// 1 + 100 * (1 + 10 * (1 + 10 * 1)) = 11101
+		{"too costly", `{ leagues(limit: 100) { teams { matchups { id } } } }`, "query complexity 11101 exceeds the maximum of 2000"},
+		// 1 + 5 * (1 + 10 * 1) = 56
+		{"small page", `{ leagues(limit: 5) { teams { id } } }`, ""},
+		{"variable limit counts as the largest page", `query ($n: Int) { leagues(limit: $n) { teams { matchups { id } } } }`, "query complexity 11101"},
+		{"typename is free", `{ __typename leagues(limit: 5) { __typename teams { id } } }`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = CheckLimits(schema, doc, limits)
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("got %v, want no error", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("got %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package graph
+
+import (
+	"context"
+	"sync"
+
+	"github.com/layer8s/home-dashboard-app/internal/db"
+)
+
+type contextKey string
+
+const loadersContextKey = contextKey("loaders")
+
+// Loaders batches the lookups made while resolving a single query. Resolvers ask a
+// loader for a key and get back a thunk; graphql-go resolves thunks breadth first,
+// so by the time the first thunk at a given depth runs, every key requested at that
+// depth is queued and they are all fetched with one query. A new set of loaders
+// must be created for every request.
+type Loaders struct {
+	leagues          *loader[int32, db.League]
+	teams            *loader[int32, db.Team]
+	teamsByLeague    *loader[int32, []db.Team]
+	teamsByOwner     *loader[string, []db.Team]
+	matchupsByTeam   *loader[int32, []db.Matchup]
+	draftsByTeam     *loader[int32, []db.Draft]
+	activitiesByTeam *loader[int32, []db.Activity]
+	players          *loader[int32, db.Player]
+}
+
+func NewLoaders(queries *db.Queries) *Loaders {
+	return &Loaders{
+		leagues: newLoader(func(ctx context.Context, ids []int32) (map[int32]db.League, error) {
+			rows, err := queries.ListLeaguesByIDs(ctx, ids)
+			return indexBy(rows, err, func(l db.League) int32 { return l.ID })
+		}),
+		teams: newLoader(func(ctx context.Context, ids []int32) (map[int32]db.Team, error) {
+			rows, err := queries.ListTeamsByIDs(ctx, ids)
+			return indexBy(rows, err, func(t db.Team) int32 { return t.ID })
+		}),
+		teamsByLeague: newLoader(func(ctx context.Context, ids []int32) (map[int32][]db.Team, error) {
+			rows, err := queries.ListTeamsByLeagueIDs(ctx, ids)
+			return groupBy(ids, rows, err, func(t db.Team) []int32 { return []int32{t.LeagueID} })
+		}),
+		teamsByOwner: newLoader(func(ctx context.Context, owners []string) (map[string][]db.Team, error) {
+			rows, err := queries.ListTeamsByOwners(ctx, owners)
+			return groupBy(owners, rows, err, func(t db.Team) []string { return []string{t.Owners.String} })
+		}),
+		matchupsByTeam: newLoader(func(ctx context.Context, ids []int32) (map[int32][]db.Matchup, error) {
+			rows, err := queries.ListMatchupsByTeamIDs(ctx, ids)
+			return groupBy(ids, rows, err, func(m db.Matchup) []int32 {
+				return []int32{m.HomeTeamID.Int32, m.AwayTeamID.Int32}
+			})
+		}),
+		draftsByTeam: newLoader(func(ctx context.Context, ids []int32) (map[int32][]db.Draft, error) {
+			rows, err := queries.ListDraftsByTeamIDs(ctx, ids)
+			return groupBy(ids, rows, err, func(d db.Draft) []int32 { return []int32{d.TeamID} })
+		}),
+		activitiesByTeam: newLoader(func(ctx context.Context, ids []int32) (map[int32][]db.Activity, error) {
+			rows, err := queries.ListActivitiesByTeamIDs(ctx, ids)
+			return groupBy(ids, rows, err, func(a db.Activity) []int32 { return []int32{a.TeamID.Int32} })
+		}),
+		players: newLoader(func(ctx context.Context, ids []int32) (map[int32]db.Player, error) {
+			rows, err := queries.ListPlayersByIDs(ctx, ids)
+			return indexBy(rows, err, func(p db.Player) int32 { return p.ID })
+		}),
+	}
+}
+
+// ContextWithLoaders returns a copy of ctx carrying the loaders for one request.
+func ContextWithLoaders(ctx context.Context, l *Loaders) context.Context {
+	return context.WithValue(ctx, loadersContextKey, l)
+}
+
+func loadersFromContext(ctx context.Context) *Loaders {
+	l, ok := ctx.Value(loadersContextKey).(*Loaders)
+	if !ok {
+		panic("missing loaders in context")
+	}
+	return l
+}
+
+type loaderResult[V any] struct {
+	value V
+	found bool
+	err   error
+}
+
+type loader[K comparable, V any] struct {
+	fetch   func(ctx context.Context, keys []K) (map[K]V, error)
+	mu      sync.Mutex
+	pending []K
+	queued  map[K]bool
+	results map[K]loaderResult[V]
+}
+
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:   fetch,
+		queued:  make(map[K]bool),
+		results: make(map[K]loaderResult[V]),
+	}
+}
+
+// load queues key for the next batch and returns a thunk that yields its value. A
+// key that was not found resolves to nil.
+func (l *loader[K, V]) load(ctx context.Context, key K) func() (interface{}, error) {
+	l.mu.Lock()
+	if _, done := l.results[key]; !done && !l.queued[key] {
+		l.pending = append(l.pending, key)
+		l.queued[key] = true
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if _, done := l.results[key]; !done {
+			l.dispatch(ctx)
+		}
+		result := l.results[key]
+		if result.err != nil || !result.found {
+			return nil, result.err
+		}
+		return result.value, nil
+	}
+}
+
+// dispatch fetches every pending key in one call. The caller must hold l.mu.
+func (l *loader[K, V]) dispatch(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+	clear(l.queued)
+
+	values, err := l.fetch(ctx, keys)
+	for _, key := range keys {
+		value, found := values[key]
+		l.results[key] = loaderResult[V]{value: value, found: found, err: err}
+	}
+}
+
+func indexBy[K comparable, V any](rows []V, err error, key func(V) K) (map[K]V, error) {
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[K]V, len(rows))
+	for _, row := range rows {
+		out[key(row)] = row
+	}
+	return out, nil
+}
+
+// groupBy buckets rows under each of the requested keys. Every requested key gets an
+// entry, so keys without rows resolve to an empty list rather than null.
+func groupBy[K comparable, V any](keys []K, rows []V, err error, keysOf func(V) []K) (map[K][]V, error) {
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[K][]V, len(keys))
+	for _, key := range keys {
+		out[key] = []V{}
+	}
+	for _, row := range rows {
+		for _, key := range keysOf(row) {
+			if _, requested := out[key]; requested {
+				out[key] = append(out[key], row)
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/graphql-go/graphql"
+	"github.com/layer8s/home-dashboard-app/internal/db"
+)
+
+var (
+	leagueColumns  = []string{"id", "leagueId", "year", "teamCount", "currentWeek", "nflWeek"}
+	teamColumns    = []string{"id", "league_id", "teamId", "year", "teamAbbrv", "teamName", "owners", "divisionId", "divisionName", "wins", "losses", "ties", "pointsFor", "pointsAgainst", "waiverRank", "acquisitions", "acquisitionBudgetSpent", "drops", "trades", "streakType", "streakLength", "standing", "finalStanding", "draftProjRank", "playoffPct", "logoUrl"}
+	matchupColumns = []string{"id", "week", "home_team_id", "away_team_id", "homeScore", "awayScore", "isPlayoff", "matchupType"}
+)
+
+func teamRow(id, leagueID int32) []driver.Value {
+	row := make([]driver.Value, len(teamColumns))
+	copy(row, []driver.Value{id, leagueID, id, 2024, "T", "Team", "Owner"})
+	return row
+}
+
+func newTestSchema(t *testing.T) (graphql.Schema, *db.Queries, sqlmock.Sqlmock) {
+	t.Helper()
+
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	queries := db.New(conn)
+	schema, err := NewSchema(queries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema, queries, mock
+}
+
+// TestLoadersBatchByDepth checks that a nested query makes one query per level of
+// nesting, however many parents each level has, rather than one per parent.
+func TestLoadersBatchByDepth(t *testing.T) {
+	schema, queries, mock := newTestSchema(t)
+	// Loaders at the same depth may dispatch in either order.
+	mock.MatchExpectationsInOrder(false)
+
+	mock.ExpectQuery("-- name: GetLeaguesAsc").
+		WillReturnRows(sqlmock.NewRows(leagueColumns).
+			AddRow(1, 101, 2024, 2, 17, 18).
+			AddRow(2, 102, 2024, 2, 17, 18).
+			AddRow(3, 103, 2024, 2, 17, 18))
+	mock.ExpectQuery("-- name: ListTeamsByLeagueIDs").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(teamColumns).
+			AddRow(teamRow(11, 1)...).AddRow(teamRow(12, 1)...).
+			AddRow(teamRow(21, 2)...).AddRow(teamRow(22, 2)...).
+			AddRow(teamRow(31, 3)...).AddRow(teamRow(32, 3)...))
+	mock.ExpectQuery("-- name: ListMatchupsByTeamIDs").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(matchupColumns).
+			AddRow(1, 1, 11, 12, 100.5, 90.25, false, nil).
+			AddRow(2, 1, 21, 22, 80.0, 85.0, false, nil))
+	mock.ExpectQuery("-- name: ListLeaguesByIDs").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(leagueColumns).
+			AddRow(1, 101, 2024, 2, 17, 18).
+			AddRow(2, 102, 2024, 2, 17, 18).
+			AddRow(3, 103, 2024, 2, 17, 18))
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ leagues(limit: 3) { id teams { id league { id } matchups { id homeScore } } } }`,
+		Context:       ContextWithLoaders(context.Background(), NewLoaders(queries)),
+	})
+	if result.HasErrors() {
+		t.Fatal(result.Errors)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	leagues := result.Data.(map[string]any)["leagues"].([]any)
+	if len(leagues) != 3 {
+		t.Fatalf("got %d leagues, want 3", len(leagues))
+	}
+	teams := leagues[0].(map[string]any)["teams"].([]any)
+	if len(teams) != 2 {
+		t.Fatalf("got %d teams in the first league, want 2", len(teams))
+	}
+	if matchups := teams[0].(map[string]any)["matchups"].([]any); len(matchups) != 1 {
+		t.Errorf("got %d matchups for the first team, want 1", len(matchups))
+	}
+	if matchups := teams[len(teams)-1].(map[string]any)["matchups"].([]any); len(matchups) != 1 {
+		t.Errorf("got %d matchups for the second team, want 1", len(matchups))
+	}
+}
+
+// TestLoaderDeduplicatesKeys checks that asking for the same key twice at one
+// depth fetches it once.
+func TestLoaderDeduplicatesKeys(t *testing.T) {
+	schema, queries, mock := newTestSchema(t)
+
+	mock.ExpectQuery("-- name: ListTeamsByIDs").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(teamColumns).AddRow(teamRow(11, 1)...))
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ a: team(id: 11) { id } b: team(id: 11) { id } missing: team(id: 99) { id } }`,
+		Context:       ContextWithLoaders(context.Background(), NewLoaders(queries)),
+	})
+	if result.HasErrors() {
+		t.Fatal(result.Errors)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+	if data := result.Data.(map[string]any); data["missing"] != nil {
+		t.Errorf("missing team resolved to %v, want null", data["missing"])
+	}
+}
@@ -0,0 +1,348 @@
+// Package graph exposes the fantasy football archive as a read-only GraphQL schema.
+package graph
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/graphql-go/graphql"
+	"github.com/layer8s/home-dashboard-app/internal/db"
+)
+
+// NewSchema builds the archive schema. Root resolvers run queries directly; every
+// nested relation goes through the loaders found in the request context.
+func NewSchema(queries *db.Queries) (graphql.Schema, error) {
+	var (
+		leagueType   *graphql.Object
+		teamType     *graphql.Object
+		ownerType    *graphql.Object
+		matchupType  *graphql.Object
+		draftType    *graphql.Object
+		activityType *graphql.Object
+	)
+
+	playerType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Player",
+		Fields: graphql.Fields{
+			"id":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"espnId":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"name":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"position": nullableField(graphql.String),
+		},
+	})
+
+	leagueType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "League",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"leagueId":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"year":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"teamCount":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"currentWeek": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"nflWeek":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"teams": &graphql.Field{
+					Type: listOf(teamType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						league := p.Source.(db.League)
+						return loadersFromContext(p.Context).teamsByLeague.load(p.Context, league.ID), nil
+					},
+				},
+			}
+		}),
+	})
+
+	ownerType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Owner",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"name": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return string(p.Source.(owner)), nil
+					},
+				},
+				"teams": &graphql.Field{
+					Type: listOf(teamType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadersFromContext(p.Context).teamsByOwner.load(p.Context, string(p.Source.(owner))), nil
+					},
+				},
+			}
+		}),
+	})
+
+	teamType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Team",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":                     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"teamId":                 &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"year":                   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"teamAbbrv":              &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"teamName":               &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"divisionId":             nullableField(graphql.String),
+				"divisionName":           nullableField(graphql.String),
+				"wins":                   nullableField(graphql.Int),
+				"losses":                 nullableField(graphql.Int),
+				"ties":                   nullableField(graphql.Int),
+				"pointsFor":              nullableField(graphql.Int),
+				"pointsAgainst":          nullableField(graphql.Int),
+				"waiverRank":             nullableField(graphql.Int),
+				"acquisitions":           nullableField(graphql.Int),
+				"acquisitionBudgetSpent": nullableField(graphql.Int),
+				"drops":                  nullableField(graphql.Int),
+				"trades":                 nullableField(graphql.Int),
+				"streakType":             nullableField(graphql.String),
+				"streakLength":           nullableField(graphql.Int),
+				"standing":               nullableField(graphql.Int),
+				"finalStanding":          nullableField(graphql.Int),
+				"draftProjRank":          nullableField(graphql.Int),
+				"playoffPct":             nullableField(graphql.Int),
+				"logoUrl":                nullableField(graphql.String),
+				"league": &graphql.Field{
+					Type: leagueType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						team := p.Source.(db.Team)
+						return loadersFromContext(p.Context).leagues.load(p.Context, team.LeagueID), nil
+					},
+				},
+				"owner": &graphql.Field{
+					Type: ownerType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						team := p.Source.(db.Team)
+						if !team.Owners.Valid {
+							return nil, nil
+						}
+						return owner(team.Owners.String), nil
+					},
+				},
+				"matchups": &graphql.Field{
+					Type: listOf(matchupType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						team := p.Source.(db.Team)
+						return loadersFromContext(p.Context).matchupsByTeam.load(p.Context, team.ID), nil
+					},
+				},
+				"drafts": &graphql.Field{
+					Type: listOf(draftType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						team := p.Source.(db.Team)
+						return loadersFromContext(p.Context).draftsByTeam.load(p.Context, team.ID), nil
+					},
+				},
+				"activities": &graphql.Field{
+					Type: listOf(activityType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						team := p.Source.(db.Team)
+						return loadersFromContext(p.Context).activitiesByTeam.load(p.Context, team.ID), nil
+					},
+				},
+			}
+		}),
+	})
+
+	matchupType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Matchup",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"week":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"homeScore":   nullableField(graphql.Float),
+				"awayScore":   nullableField(graphql.Float),
+				"isPlayoff":   nullableField(graphql.Boolean),
+				"matchupType": nullableField(graphql.String),
+				"homeTeam": &graphql.Field{
+					Type: teamType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadTeam(p, p.Source.(db.Matchup).HomeTeamID), nil
+					},
+				},
+				"awayTeam": &graphql.Field{
+					Type: teamType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadTeam(p, p.Source.(db.Matchup).AwayTeamID), nil
+					},
+				},
+			}
+		}),
+	})
+
+	draftType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "DraftPick",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":           &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"overallPick":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"roundNum":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"roundPick":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"keeperStatus": nullableField(graphql.Boolean),
+				"bidAmount":    nullableField(graphql.Int),
+				"team": &graphql.Field{
+					Type: teamType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						draft := p.Source.(db.Draft)
+						return loadersFromContext(p.Context).teams.load(p.Context, draft.TeamID), nil
+					},
+				},
+				"nominatingTeam": &graphql.Field{
+					Type: teamType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadTeam(p, p.Source.(db.Draft).NominatingTeamID), nil
+					},
+				},
+				"player": &graphql.Field{
+					Type: playerType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						draft := p.Source.(db.Draft)
+						return loadersFromContext(p.Context).players.load(p.Context, draft.PlayerID), nil
+					},
+				},
+			}
+		}),
+	})
+
+	activityType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Activity",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"date":      nullableField(graphql.Float),
+				"action":    nullableField(graphql.String),
+				"bidAmount": nullableField(graphql.Float),
+				"team": &graphql.Field{
+					Type: teamType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadTeam(p, p.Source.(db.Activity).TeamID), nil
+					},
+				},
+				"player": &graphql.Field{
+					Type: playerType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						activity := p.Source.(db.Activity)
+						return loadersFromContext(p.Context).players.load(p.Context, activity.PlayerID), nil
+					},
+				},
+			}
+		}),
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"leagues": &graphql.Field{
+				Type: listOf(leagueType),
+				Args: graphql.FieldConfigArgument{
+					"year":   &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: -1},
+					"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 20},
+					"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit := p.Args["limit"].(int)
+					offset := p.Args["offset"].(int)
+					if limit < 1 || limit > MaxListLimit {
+						return nil, errors.New("limit must be between 1 and 100")
+					}
+					if offset < 0 {
+						return nil, errors.New("offset must not be negative")
+					}
+					leagues, err := queries.GetLeaguesAsc(p.Context, db.GetLeaguesAscParams{
+						ID:          -1,
+						LeagueId:    -1,
+						Year:        int32(p.Args["year"].(int)),
+						TeamCount:   -1,
+						CurrentWeek: -1,
+						NflWeek:     -1,
+						Limit:       int32(limit),
+						Offset:      int32(offset),
+						Column9:     "id",
+					})
+					if err != nil {
+						return nil, err
+					}
+					if leagues == nil {
+						leagues = []db.League{}
+					}
+					return leagues, nil
+				},
+			},
+			"league": &graphql.Field{
+				Type: leagueType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFromContext(p.Context).leagues.load(p.Context, int32(p.Args["id"].(int))), nil
+				},
+			},
+			"team": &graphql.Field{
+				Type: teamType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFromContext(p.Context).teams.load(p.Context, int32(p.Args["id"].(int))), nil
+				},
+			},
+			"owners": &graphql.Field{
+				Type: listOf(ownerType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					rows, err := queries.ListOwners(p.Context)
+					if err != nil {
+						return nil, err
+					}
+					owners := make([]owner, 0, len(rows))
+					for _, row := range rows {
+						owners = append(owners, owner(row.String))
+					}
+					return owners, nil
+				},
+			},
+			"owner": &graphql.Field{
+				Type: ownerType,
+				Args: graphql.FieldConfigArgument{
+					"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return owner(p.Args["name"].(string)), nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+// owner is the display name stored in teams.owners. The archive has no separate
+// owners table, so an owner is identified by that name alone.
+type owner string
+
+func listOf(t graphql.Type) graphql.Output {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t)))
+}
+
+// nullableField declares a field backed by one of the sql.Null* types, which are
+// unwrapped to their value or null.
+func nullableField(t graphql.Output) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			value, err := graphql.DefaultResolveFn(p)
+			if err != nil {
+				return nil, err
+			}
+			if valuer, ok := value.(driver.Valuer); ok {
+				return valuer.Value()
+			}
+			return value, nil
+		},
+	}
+}
+
+func loadTeam(p graphql.ResolveParams, id sql.NullInt32) interface{} {
+	if !id.Valid {
+		return nil
+	}
+	return loadersFromContext(p.Context).teams.load(p.Context, id.Int32)
+}
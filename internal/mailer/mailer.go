@@ -2,21 +2,36 @@ package mailer
 
 import (
 	"bytes"
+	"context"
 	"embed"
 	"fmt"
 	"html/template"
 	"log/slog"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/layer8s/home-dashboard-app/internal/mailer")
+
 // Declare a new variable with the type embed.FS (embedded file system) to hold email templates.
 
 //go:embed "templates"
 var templateFS embed.FS
 
+// SendsTotal counts emails by template and outcome ("sent" or "failed", after
+// retries). It's registered with the application's metrics registry.
+var SendsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "mailer_sends_total",
+	Help: "Emails sent, by template and outcome.",
+}, []string{"template", "outcome"})
+
 type Mailer struct {
 	client *sendgrid.Client
 	sender string
@@ -32,7 +47,21 @@ func New(apiKey, senderEmail string, logger *slog.Logger) *Mailer {
 	}
 }
 
-func (m Mailer) Send(recipient, templateFile string, data any) error {
+// Send emails recipient the message in templateFile, rendered with data. The
+// call to SendGrid is traced as a child of ctx.
+func (m Mailer) Send(ctx context.Context, recipient, templateFile string, data any) error {
+	err := m.send(ctx, recipient, templateFile, data)
+
+	outcome := "sent"
+	if err != nil {
+		outcome = "failed"
+	}
+	SendsTotal.WithLabelValues(templateFile, outcome).Inc()
+
+	return err
+}
+
+func (m Mailer) send(ctx context.Context, recipient, templateFile string, data any) error {
 	tmpl, err := template.New("email").ParseFS(templateFS, "templates/"+templateFile)
 	if err != nil {
 		return err
@@ -66,9 +95,15 @@ func (m Mailer) Send(recipient, templateFile string, data any) error {
 	htmlContent := htmlBody.String()
 	message := mail.NewSingleEmail(from, subject.String(), to, plainTextContent, htmlContent)
 
+	ctx, span := tracer.Start(ctx, "sendgrid send",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("mailer.template", templateFile)))
+	defer span.End()
+
 	for i := 1; i <= 3; i++ {
-		response, err := m.client.Send(message)
-		if nil == err {
+		span.AddEvent("attempt", trace.WithAttributes(attribute.Int("attempt", i)))
+		response, sendErr := m.client.SendWithContext(ctx, message)
+		if nil == sendErr {
 			// If the email is sent successfully, break out of the loop.
 			m.logger.Info("email sent successfully",
 				"status", response.StatusCode,
@@ -79,6 +114,7 @@ func (m Mailer) Send(recipient, templateFile string, data any) error {
 
 		}
 
+		err = sendErr
 		m.logger.Error("failed to send email",
 			"error", err,
 			"recipient", recipient,
@@ -88,6 +124,8 @@ func (m Mailer) Send(recipient, templateFile string, data any) error {
 		time.Sleep(500 * time.Millisecond)
 
 	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
 	return fmt.Errorf("error sending email: %w", err)
 
 }
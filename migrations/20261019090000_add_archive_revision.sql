@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS archive_revision (
+    id boolean PRIMARY KEY DEFAULT true CHECK (id),
+    revision bigint NOT NULL DEFAULT 1,
+    current_season integer NOT NULL DEFAULT 0,
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO archive_revision (id, current_season)
+VALUES (true, COALESCE((SELECT max("year") FROM "leagues"), 0))
+ON CONFLICT (id) DO NOTHING;
+
+CREATE OR REPLACE FUNCTION bump_archive_revision() RETURNS trigger AS $$
+BEGIN
+    UPDATE archive_revision
+    SET revision = revision + 1,
+        current_season = COALESCE((SELECT max("year") FROM "leagues"), 0),
+        updated_at = NOW();
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER leagues_archive_revision AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON "leagues"
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_archive_revision();
+CREATE TRIGGER teams_archive_revision AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON "teams"
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_archive_revision();
+CREATE TRIGGER players_archive_revision AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON "players"
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_archive_revision();
+CREATE TRIGGER drafts_archive_revision AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON "drafts"
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_archive_revision();
+CREATE TRIGGER matchups_archive_revision AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON "matchups"
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_archive_revision();
+CREATE TRIGGER activities_archive_revision AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON "activities"
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_archive_revision();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS activities_archive_revision ON "activities";
+DROP TRIGGER IF EXISTS matchups_archive_revision ON "matchups";
+DROP TRIGGER IF EXISTS drafts_archive_revision ON "drafts";
+DROP TRIGGER IF EXISTS players_archive_revision ON "players";
+DROP TRIGGER IF EXISTS teams_archive_revision ON "teams";
+DROP TRIGGER IF EXISTS leagues_archive_revision ON "leagues";
+DROP FUNCTION IF EXISTS bump_archive_revision();
+DROP TABLE IF EXISTS archive_revision;
+-- +goose StatementEnd
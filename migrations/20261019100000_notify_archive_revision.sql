@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION bump_archive_revision() RETURNS trigger AS $$
+DECLARE
+    new_revision bigint;
+BEGIN
+    UPDATE archive_revision
+    SET revision = revision + 1,
+        current_season = COALESCE((SELECT max("year") FROM "leagues"), 0),
+        updated_at = NOW()
+    RETURNING revision INTO new_revision;
+    -- Delivered when the importing transaction commits.
+    PERFORM pg_notify('archive_revision', new_revision::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION bump_archive_revision() RETURNS trigger AS $$
+BEGIN
+    UPDATE archive_revision
+    SET revision = revision + 1,
+        current_season = COALESCE((SELECT max("year") FROM "leagues"), 0),
+        updated_at = NOW();
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
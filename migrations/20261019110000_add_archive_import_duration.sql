@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE archive_revision ADD COLUMN last_import_seconds double precision NOT NULL DEFAULT 0;
+
+-- NOW() is the start of the importing transaction while clock_timestamp() is the
+-- current time, so after the last statement of an import this holds its duration.
+CREATE OR REPLACE FUNCTION bump_archive_revision() RETURNS trigger AS $$
+DECLARE
+    new_revision bigint;
+BEGIN
+    UPDATE archive_revision
+    SET revision = revision + 1,
+        current_season = COALESCE((SELECT max("year") FROM "leagues"), 0),
+        last_import_seconds = EXTRACT(EPOCH FROM clock_timestamp() - NOW()),
+        updated_at = NOW()
+    RETURNING revision INTO new_revision;
+    -- Delivered when the importing transaction commits.
+    PERFORM pg_notify('archive_revision', new_revision::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION bump_archive_revision() RETURNS trigger AS $$
+DECLARE
+    new_revision bigint;
+BEGIN
+    UPDATE archive_revision
+    SET revision = revision + 1,
+        current_season = COALESCE((SELECT max("year") FROM "leagues"), 0),
+        updated_at = NOW()
+    RETURNING revision INTO new_revision;
+    -- Delivered when the importing transaction commits.
+    PERFORM pg_notify('archive_revision', new_revision::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE archive_revision DROP COLUMN IF EXISTS last_import_seconds;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS user_identities (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    provider text NOT NULL,
+    provider_id text NOT NULL,
+    email citext,
+    email_verified bool NOT NULL DEFAULT false,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    last_login_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_identity_provider_and_provider_id UNIQUE (provider, provider_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_identities_user_id_idx ON user_identities (user_id);
+CREATE INDEX IF NOT EXISTS user_identities_verified_email_idx ON user_identities (lower(email)) WHERE email_verified;
+
+-- Every existing user signed in with exactly one provider. Whether that provider
+-- verified the address wasn't recorded, so none are treated as verified.
+INSERT INTO user_identities (user_id, provider, provider_id, email, created_at, last_login_at)
+SELECT id, provider, provider_id, email, created_at, created_at
+FROM users;
+
+ALTER TABLE users DROP CONSTRAINT unique_provider_and_provider_id;
+ALTER TABLE users DROP COLUMN provider;
+ALTER TABLE users DROP COLUMN provider_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN provider text;
+ALTER TABLE users ADD COLUMN provider_id text;
+
+-- Users with several identities keep the one they had first.
+UPDATE users
+SET provider = i.provider, provider_id = i.provider_id
+FROM (
+    SELECT DISTINCT ON (user_id) user_id, provider, provider_id
+    FROM user_identities
+    ORDER BY user_id, created_at, id
+) i
+WHERE i.user_id = users.id;
+
+DELETE FROM users WHERE provider IS NULL;
+ALTER TABLE users ALTER COLUMN provider SET NOT NULL;
+ALTER TABLE users ALTER COLUMN provider_id SET NOT NULL;
+ALTER TABLE users ADD CONSTRAINT unique_provider_and_provider_id UNIQUE (provider, provider_id);
+
+DROP TABLE IF EXISTS user_identities;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The original tokens migration was never enabled, so the table only exists
+-- where it was created from schema.sql.
+CREATE TABLE IF NOT EXISTS tokens (
+    hash bytea PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    expiry timestamp(0) with time zone NOT NULL,
+    scope text NOT NULL
+);
+
+-- Personal API tokens are listed and revoked by ID and name, and may never
+-- expire.
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS id bigserial UNIQUE;
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS name text NOT NULL DEFAULT '';
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS created_at timestamp(0) with time zone NOT NULL DEFAULT NOW();
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS last_used_at timestamp(0) with time zone;
+ALTER TABLE tokens ALTER COLUMN expiry DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS tokens_user_id_scope_idx ON tokens (user_id, scope);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS tokens_user_id_scope_idx;
+
+DELETE FROM tokens WHERE expiry IS NULL;
+ALTER TABLE tokens ALTER COLUMN expiry SET NOT NULL;
+ALTER TABLE tokens DROP COLUMN IF EXISTS last_used_at;
+ALTER TABLE tokens DROP COLUMN IF EXISTS created_at;
+ALTER TABLE tokens DROP COLUMN IF EXISTS name;
+ALTER TABLE tokens DROP COLUMN IF EXISTS id;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN password_hash bytea;
+
+-- Only one password account per address; accounts from identity providers may
+-- still share one.
+CREATE UNIQUE INDEX IF NOT EXISTS users_password_email_idx ON users (lower(email)) WHERE password_hash IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Users who can only sign in with a password can't sign in at all without it.
+DELETE FROM users
+WHERE password_hash IS NOT NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM user_identities
+        WHERE user_identities.user_id = users.id AND user_identities.provider <> 'password'
+    );
+DELETE FROM user_identities WHERE provider = 'password';
+
+DROP INDEX IF EXISTS users_password_email_idx;
+ALTER TABLE users DROP COLUMN IF EXISTS password_hash;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Roles are granted on an ESPN league ("leagueId"), so they carry over from one
+-- season's row in leagues to the next.
+CREATE TABLE IF NOT EXISTS league_roles (
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    league_id integer NOT NULL,
+    role text NOT NULL CHECK (role IN ('admin', 'commissioner', 'member', 'viewer')),
+    granted_by bigint REFERENCES users ON DELETE SET NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, league_id)
+);
+
+CREATE INDEX IF NOT EXISTS league_roles_league_id_idx ON league_roles (league_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS league_roles;
+-- +goose StatementEnd
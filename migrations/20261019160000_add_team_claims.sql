@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A claim ties a user to the teams they managed in a league, either by the owner
+-- name the archive records ("owners") or by ESPN team ID, across every season.
+CREATE TABLE IF NOT EXISTS team_claims (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    league_id integer NOT NULL,
+    owner text,
+    team_id integer,
+    status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'revoked', 'withdrawn')),
+    message text NOT NULL DEFAULT '',
+    decided_by bigint REFERENCES users ON DELETE SET NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    CHECK ((owner IS NULL) <> (team_id IS NULL))
+);
+
+-- A user can only have one open claim on the same owner or team.
+CREATE UNIQUE INDEX IF NOT EXISTS team_claims_open_idx ON team_claims (user_id, league_id, COALESCE(owner, ''), COALESCE(team_id, 0))
+    WHERE status IN ('pending', 'approved');
+CREATE INDEX IF NOT EXISTS team_claims_league_id_idx ON team_claims (league_id, status);
+
+-- Every change to a claim, including the request itself, is kept here.
+CREATE TABLE IF NOT EXISTS team_claim_events (
+    id bigserial PRIMARY KEY,
+    claim_id bigint NOT NULL REFERENCES team_claims ON DELETE CASCADE,
+    status text NOT NULL,
+    actor_id bigint REFERENCES users ON DELETE SET NULL,
+    note text NOT NULL DEFAULT '',
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS team_claim_events_claim_id_idx ON team_claim_events (claim_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS team_claim_events;
+DROP TABLE IF EXISTS team_claims;
+-- +goose StatementEnd
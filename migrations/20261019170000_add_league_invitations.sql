@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- An invitation to join a league with a role, and optionally as the manager of a
+-- team, sent to an email address. Only a hash of its token is kept.
+CREATE TABLE IF NOT EXISTS league_invitations (
+    id bigserial PRIMARY KEY,
+    hash bytea NOT NULL UNIQUE,
+    league_id integer NOT NULL,
+    email citext NOT NULL,
+    role text NOT NULL CHECK (role IN ('admin', 'commissioner', 'member', 'viewer')),
+    owner text,
+    team_id integer,
+    invited_by bigint REFERENCES users ON DELETE SET NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    expires_at timestamp(0) with time zone NOT NULL,
+    accepted_at timestamp(0) with time zone,
+    accepted_by bigint REFERENCES users ON DELETE SET NULL,
+    revoked_at timestamp(0) with time zone,
+    CHECK (owner IS NULL OR team_id IS NULL)
+);
+
+CREATE INDEX IF NOT EXISTS league_invitations_league_id_idx ON league_invitations (league_id);
+CREATE INDEX IF NOT EXISTS league_invitations_open_email_idx ON league_invitations (email) WHERE accepted_at IS NULL AND revoked_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS league_invitations;
+-- +goose StatementEnd